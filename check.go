@@ -0,0 +1,121 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"slices"
+	"text/tabwriter"
+	"time"
+
+	"github.com/alecthomas/kingpin/v2"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/prometheus/mysqld_exporter/collector"
+)
+
+var (
+	checkCmd = kingpin.Command(
+		"check",
+		"Validate the configuration, connect to every configured target and run each enabled collector once.",
+	)
+	checkTimeout = checkCmd.Flag(
+		"check.timeout",
+		"Timeout for checking a single target.",
+	).Default("30s").Duration()
+)
+
+// checkSection scrapes the target of a config section once with all scrapers
+// and returns the result.
+func checkSection(ctx context.Context, authModule, dsn string, scrapers []collector.Scraper, logger *slog.Logger) collector.ScrapeResult {
+	var result collector.ScrapeResult
+	ctx, cancel := context.WithTimeout(ctx, *checkTimeout)
+	defer cancel()
+
+	opts := append(exporterOpts(authModule, nil), collector.SetResultHandler(func(r collector.ScrapeResult) {
+		result = r
+	}))
+	exporter := collector.New(ctx, dsn, scrapers, logger, opts...)
+
+	ch := make(chan prometheus.Metric)
+	go func() {
+		exporter.Collect(ch)
+		close(ch)
+	}()
+	for range ch {
+	}
+	return result
+}
+
+// runCheck validates every config section and dry-runs the scrapers against
+// its target, writing a report to w. It returns false if anything failed.
+func runCheck(ctx context.Context, w io.Writer, scrapers []collector.Scraper, logger *slog.Logger) bool {
+	ok := true
+	if err := reloadConfig(logger); err != nil {
+		fmt.Fprintf(w, "Error loading config %s: %s\n", *configMycnf, err)
+		return false
+	}
+	cfg := c.GetConfig()
+	for _, section := range slices.Sorted(maps.Keys(cfg.SectionErrors)) {
+		fmt.Fprintf(w, "Invalid section [%s]: %s\n", section, cfg.SectionErrors[section])
+		ok = false
+	}
+
+	scrapers = slices.SortedFunc(slices.Values(scrapers), func(a, b collector.Scraper) int {
+		return cmp.Compare(a.Name(), b.Name())
+	})
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "SECTION\tTARGET\tCOLLECTOR\tSUCCESS\tDURATION\tSERIES\tERROR")
+	for _, section := range slices.Sorted(maps.Keys(cfg.Sections)) {
+		dsn, err := cfg.Sections[section].FormDSN("")
+		if err != nil {
+			fmt.Fprintf(tw, "%s\t\tconnection\tfalse\t\t\t%s\n", section, err)
+			ok = false
+			continue
+		}
+		result := checkSection(ctx, section, dsn, scrapers, logger)
+		if result.Err != nil {
+			fmt.Fprintf(tw, "%s\t%s\tconnection\tfalse\t%s\t\t%s\n", section, result.Target, result.Duration.Round(time.Millisecond), result.Err)
+			ok = false
+			continue
+		}
+		fmt.Fprintf(tw, "%s\t%s\tconnection\ttrue\t\t\t%s %s\n", section, result.Target, result.Flavor, result.Version)
+
+		collectors := make(map[string]collector.CollectorResult, len(result.Collectors))
+		for _, c := range result.Collectors {
+			collectors[c.Name] = c
+		}
+		for _, scraper := range scrapers {
+			c, ran := collectors[scraper.Name()]
+			if !ran {
+				fmt.Fprintf(tw, "%s\t%s\t%s\tskipped\t\t\trequires version %.1f\n", section, result.Target, scraper.Name(), scraper.Version())
+				continue
+			}
+			errMsg := ""
+			if c.Err != nil {
+				errMsg = c.Err.Error()
+				ok = false
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%t\t%s\t%d\t%s\n", section, result.Target, c.Name, c.Err == nil, c.Duration.Round(time.Millisecond), c.Series, errMsg)
+		}
+	}
+	tw.Flush()
+	return ok
+}
@@ -0,0 +1,56 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/promslog"
+
+	"github.com/prometheus/mysqld_exporter/collector"
+	"github.com/prometheus/mysqld_exporter/config"
+)
+
+func TestRunCheck(t *testing.T) {
+	cnf := filepath.Join(t.TempDir(), "my.cnf")
+	if err := os.WriteFile(cnf, []byte("[client]\nuser = foo\nhost = 127.0.0.1\nport = 1\n[other]\npassword = bar\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	configMycnf = &cnf
+	address := "localhost:3306"
+	mysqldAddress = &address
+	timeout := 5 * time.Second
+	checkTimeout = &timeout
+	defer func() { c.Config = &config.Config{} }()
+
+	var out bytes.Buffer
+	ok := runCheck(context.Background(), &out, []collector.Scraper{collector.ScrapeGlobalStatus{}}, promslog.NewNopLogger())
+	if ok {
+		t.Fatal("check against an unreachable target should fail")
+	}
+	for _, re := range []string{
+		`Invalid section \[other\]`,
+		`client\s+127\.0\.0\.1:1\s+connection\s+false`,
+	} {
+		if !regexp.MustCompile(re).Match(out.Bytes()) {
+			t.Errorf("output does not match %q:\n%s", re, out.String())
+		}
+	}
+}
@@ -0,0 +1,146 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Suspension of collectors failing with permanent errors.
+
+package collector
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var collectorSuspendedDesc = prometheus.NewDesc(
+	prometheus.BuildFQName(namespace, exporter, "collector_suspended"),
+	"Whether a collector is suspended after failing with a permanent error, such as access denied.",
+	[]string{"collector"}, nil,
+)
+
+// permanentErrors are MySQL error numbers that do not go away without a change
+// of privileges, schema or configuration.
+// See: https://dev.mysql.com/doc/mysql-errors/8.0/en/server-error-reference.html
+var permanentErrors = map[uint16]bool{
+	1044: true, // ER_DBACCESS_DENIED_ERROR
+	1109: true, // ER_UNKNOWN_TABLE
+	1142: true, // ER_TABLEACCESS_DENIED_ERROR
+	1143: true, // ER_COLUMNACCESS_DENIED_ERROR
+	1146: true, // ER_NO_SUCH_TABLE
+	1193: true, // ER_UNKNOWN_SYSTEM_VARIABLE
+	1227: true, // ER_SPECIFIC_ACCESS_DENIED_ERROR
+}
+
+// isPermanentError reports whether err will recur on every scrape until the
+// server is reconfigured.
+func isPermanentError(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && permanentErrors[mysqlErr.Number]
+}
+
+// CollectorBackoff suspends collectors that fail with a permanent error, per
+// target, with an exponentially growing suspension. A suspended collector is
+// run again once its suspension expired, and resumes if it succeeds. A
+// suspension not renewed for max after it expired is forgotten, so that
+// targets and collectors no longer scraped do not accumulate. It is safe for
+// concurrent use by multiple Exporters.
+type CollectorBackoff struct {
+	initial time.Duration
+	max     time.Duration
+	now     func() time.Time
+
+	mu        sync.Mutex
+	suspended map[backoffKey]*backoffState
+}
+
+type backoffKey struct {
+	target    string
+	collector string
+}
+
+type backoffState struct {
+	until    time.Time
+	interval time.Duration
+	err      error
+}
+
+// NewCollectorBackoff returns a CollectorBackoff suspending collectors for
+// initial after their first permanent error, doubling up to max.
+func NewCollectorBackoff(initial, max time.Duration) *CollectorBackoff {
+	return &CollectorBackoff{
+		initial:   initial,
+		max:       max,
+		now:       time.Now,
+		suspended: make(map[backoffKey]*backoffState),
+	}
+}
+
+// SetCollectorBackoff skips collectors suspended by b and reports their
+// permanent errors to it.
+func SetCollectorBackoff(b *CollectorBackoff) ExporterOpt {
+	return func(e *Exporter) {
+		e.backoff = b
+	}
+}
+
+// check returns the error that suspended the collector, or nil if it should
+// run.
+func (b *CollectorBackoff) check(target, collector string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	s, ok := b.suspended[backoffKey{target, collector}]
+	if !ok || !b.now().Before(s.until) {
+		return nil
+	}
+	return s.err
+}
+
+// record updates the suspension of the collector with the result of a run. It
+// returns how long the collector is suspended if err is permanent, zero
+// otherwise. Other errors leave the suspension unchanged.
+func (b *CollectorBackoff) record(target, collector string, err error) time.Duration {
+	key := backoffKey{target, collector}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err == nil {
+		delete(b.suspended, key)
+		return 0
+	}
+	if !isPermanentError(err) {
+		return 0
+	}
+	now := b.now()
+	s, ok := b.suspended[key]
+	if !ok || now.Sub(s.until) > b.max {
+		b.evict(now)
+		s = &backoffState{interval: b.initial}
+		b.suspended[key] = s
+	} else {
+		s.interval = min(2*s.interval, b.max)
+	}
+	s.err = err
+	s.until = now.Add(s.interval)
+	return s.interval
+}
+
+// evict forgets the suspensions that expired more than max ago. It must be
+// called with b.mu held.
+func (b *CollectorBackoff) evict(now time.Time) {
+	for key, s := range b.suspended {
+		if now.Sub(s.until) > b.max {
+			delete(b.suspended, key)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/smartystreets/goconvey/convey"
+)
+
+func TestCollectorBackoff(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	b := NewCollectorBackoff(time.Minute, 3*time.Minute)
+	b.now = func() time.Time { return now }
+
+	denied := fmt.Errorf("error running query: %w", &mysql.MySQLError{Number: 1142, Message: "SELECT command denied"})
+	const target, collector = "exporter@db:3306", "perf_schema.eventsstatements"
+
+	convey.Convey("Only permanent errors suspend a collector", t, func() {
+		convey.So(isPermanentError(denied), convey.ShouldBeTrue)
+		convey.So(isPermanentError(&mysql.MySQLError{Number: 1205}), convey.ShouldBeFalse)
+		convey.So(b.record(target, collector, errors.New("i/o timeout")), convey.ShouldEqual, 0)
+		convey.So(b.check(target, collector), convey.ShouldBeNil)
+	})
+
+	convey.Convey("Suspensions double up to the maximum", t, func() {
+		var suspensions []time.Duration
+		for range 4 {
+			convey.So(b.record(target, collector, denied), convey.ShouldBeGreaterThan, 0)
+			convey.So(b.check(target, collector), convey.ShouldEqual, denied)
+			convey.So(b.check("exporter@other:3306", collector), convey.ShouldBeNil)
+			suspensions = append(suspensions, b.suspended[backoffKey{target, collector}].interval)
+			now = now.Add(b.suspended[backoffKey{target, collector}].interval)
+			convey.So(b.check(target, collector), convey.ShouldBeNil)
+		}
+		convey.So(suspensions, convey.ShouldResemble, []time.Duration{time.Minute, 2 * time.Minute, 3 * time.Minute, 3 * time.Minute})
+	})
+
+	convey.Convey("A successful run resumes the collector", t, func() {
+		b.record(target, collector, nil)
+		convey.So(b.record(target, collector, denied), convey.ShouldEqual, time.Minute)
+	})
+
+	convey.Convey("Suspensions expired for longer than the maximum are forgotten", t, func() {
+		convey.So(b.record(target, collector, denied), convey.ShouldEqual, 2*time.Minute)
+		now = now.Add(10 * time.Minute)
+		convey.So(b.record("exporter@other:3306", collector, denied), convey.ShouldEqual, time.Minute)
+		convey.So(b.suspended, convey.ShouldHaveLength, 1)
+		convey.So(b.record(target, collector, denied), convey.ShouldEqual, time.Minute)
+	})
+}
@@ -70,6 +70,11 @@ func (ScrapeBinlogSize) Version() float64 {
 	return 5.1
 }
 
+// Privileges required by the Scraper.
+func (ScrapeBinlogSize) Privileges() []Privilege {
+	return []Privilege{privReplicationClient}
+}
+
 // Scrape collects data from database connection and sends it over channel as prometheus metric.
 func (ScrapeBinlogSize) Scrape(ctx context.Context, instance *instance, ch chan<- prometheus.Metric, logger *slog.Logger) error {
 	var logBin uint8
@@ -139,4 +144,4 @@ func (ScrapeBinlogSize) Scrape(ctx context.Context, instance *instance, ch chan<
 }
 
 // check interface
-var _ Scraper = ScrapeBinlogSize{}
+var _ PrivilegedScraper = ScrapeBinlogSize{}
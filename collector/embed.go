@@ -0,0 +1,125 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Lifecycle-managed collector for embedding in other programs.
+
+package collector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/promslog"
+)
+
+// CollectorOptions configures a Collector.
+type CollectorOptions struct {
+	// DSN of the server, in the go-sql-driver/mysql format.
+	DSN string
+	// Scrapers run on each collection. Scrapers with options, such as
+	// ScrapeHeartbeat, use their defaults if their Options are nil.
+	Scrapers []Scraper
+	// Logger defaults to a no-op logger.
+	Logger *slog.Logger
+	// ScrapeTimeout bounds each collection. Zero means no timeout.
+	ScrapeTimeout time.Duration
+	// Options are applied to the Exporter of each collection.
+	Options []ExporterOpt
+}
+
+// Collector is a prometheus.Collector scraping a server on each collection,
+// for programs embedding the exporter. Unlike an Exporter, it is not bound to
+// a single request and can be registered once with any Registerer.
+type Collector struct {
+	opts   CollectorOptions
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu         sync.Mutex
+	registerer prometheus.Registerer
+}
+
+// NewCollector returns a Collector for the server of the DSN. Close must be
+// called to release it.
+func NewCollector(opts CollectorOptions) (*Collector, error) {
+	if _, err := mysql.ParseDSN(opts.DSN); err != nil {
+		return nil, fmt.Errorf("invalid DSN: %w", err)
+	}
+	if len(opts.Scrapers) == 0 {
+		return nil, errors.New("no scrapers")
+	}
+	if opts.Logger == nil {
+		opts.Logger = promslog.NewNopLogger()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Collector{opts: opts, ctx: ctx, cancel: cancel}, nil
+}
+
+// exporter returns an Exporter for one collection.
+func (c *Collector) exporter(ctx context.Context) *Exporter {
+	return New(ctx, c.opts.DSN, c.opts.Scrapers, c.opts.Logger, c.opts.Options...)
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	c.exporter(c.ctx).Describe(ch)
+}
+
+// Collect implements prometheus.Collector. Collections after Close report the
+// server as down.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	ctx := c.ctx
+	if c.opts.ScrapeTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.opts.ScrapeTimeout)
+		defer cancel()
+	}
+	c.exporter(ctx).Collect(ch)
+}
+
+// Register registers the Collector with r. A Collector can only be registered
+// with one Registerer at a time.
+func (c *Collector) Register(r prometheus.Registerer) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.registerer != nil {
+		return errors.New("collector already registered")
+	}
+	if err := r.Register(c); err != nil {
+		return err
+	}
+	c.registerer = r
+	return nil
+}
+
+// Close unregisters the Collector, if registered, and aborts collections in
+// progress.
+func (c *Collector) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.registerer != nil {
+		c.registerer.Unregister(c)
+		c.registerer = nil
+	}
+	c.cancel()
+	return nil
+}
+
+// check interface
+var _ prometheus.Collector = (*Collector)(nil)
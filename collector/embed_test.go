@@ -0,0 +1,63 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/smartystreets/goconvey/convey"
+)
+
+func TestCollector(t *testing.T) {
+	convey.Convey("Invalid options are rejected", t, func() {
+		_, err := NewCollector(CollectorOptions{DSN: "root@tcp(", Scrapers: []Scraper{ScrapeGlobalStatus{}}})
+		convey.So(err, convey.ShouldNotBeNil)
+		_, err = NewCollector(CollectorOptions{DSN: dsn})
+		convey.So(err, convey.ShouldNotBeNil)
+	})
+
+	convey.Convey("Collector lifecycle", t, func() {
+		c, err := NewCollector(CollectorOptions{
+			DSN:      "root@tcp(127.0.0.1:1)/",
+			Scrapers: []Scraper{ScrapeHeartbeat{}},
+		})
+		convey.So(err, convey.ShouldBeNil)
+
+		registry := prometheus.NewRegistry()
+		convey.So(c.Register(registry), convey.ShouldBeNil)
+		convey.So(c.Register(prometheus.NewRegistry()), convey.ShouldNotBeNil)
+
+		mfs, err := registry.Gather()
+		convey.So(err, convey.ShouldBeNil)
+		up := map[string]float64{}
+		for _, mf := range mfs {
+			up[mf.GetName()] = mf.GetMetric()[0].GetGauge().GetValue()
+		}
+		convey.So(up, convey.ShouldContainKey, "mysql_up")
+		convey.So(up["mysql_up"], convey.ShouldEqual, 0)
+
+		convey.So(c.Close(), convey.ShouldBeNil)
+		mfs, err = registry.Gather()
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(mfs, convey.ShouldBeEmpty)
+
+		// A closed collector can be registered again, and reports the
+		// server as down.
+		convey.So(c.Register(registry), convey.ShouldBeNil)
+		mfs, err = registry.Gather()
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(mfs, convey.ShouldNotBeEmpty)
+	})
+}
@@ -57,6 +57,11 @@ func (ScrapeEngineInnodbStatus) Version() float64 {
 	return 5.1
 }
 
+// Privileges required by the Scraper.
+func (ScrapeEngineInnodbStatus) Privileges() []Privilege {
+	return []Privilege{privProcess}
+}
+
 // Scrape collects data from database connection and sends it over channel as prometheus metric.
 func (ScrapeEngineInnodbStatus) Scrape(ctx context.Context, instance *instance, ch chan<- prometheus.Metric, logger *slog.Logger) error {
 	db := instance.getDB()
@@ -102,4 +107,4 @@ func (ScrapeEngineInnodbStatus) Scrape(ctx context.Context, instance *instance,
 }
 
 // check interface
-var _ Scraper = ScrapeEngineInnodbStatus{}
+var _ PrivilegedScraper = ScrapeEngineInnodbStatus{}
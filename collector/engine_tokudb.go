@@ -49,6 +49,11 @@ func (ScrapeEngineTokudbStatus) Version() float64 {
 	return 5.6
 }
 
+// Privileges required by the Scraper.
+func (ScrapeEngineTokudbStatus) Privileges() []Privilege {
+	return []Privilege{privProcess}
+}
+
 // Scrape collects data from database connection and sends it over channel as prometheus metric.
 func (ScrapeEngineTokudbStatus) Scrape(ctx context.Context, instance *instance, ch chan<- prometheus.Metric, logger *slog.Logger) error {
 	db := instance.getDB()
@@ -96,4 +101,4 @@ func sanitizeTokudbMetric(metricName string) string {
 }
 
 // check interface
-var _ Scraper = ScrapeEngineTokudbStatus{}
+var _ PrivilegedScraper = ScrapeEngineTokudbStatus{}
@@ -15,14 +15,18 @@ package collector
 
 import (
 	"context"
+	"database/sql/driver"
 	"fmt"
 	"log/slog"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-sql-driver/mysql"
 	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Metric name parts.
@@ -58,6 +62,16 @@ var (
 		"Collector time duration.",
 		[]string{"collector"}, nil,
 	)
+	mysqlScrapeCollectorTimedOut = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, exporter, "collector_timed_out"),
+		"Whether a collector was still running at the scrape deadline.",
+		[]string{"collector"}, nil,
+	)
+	scrapeIncompleteDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, exporter, "scrape_incomplete"),
+		"Whether the scrape ran out of time and some collectors did not complete.",
+		nil, nil,
+	)
 )
 
 // Verify if Exporter implements prometheus.Collector
@@ -74,6 +88,51 @@ type Exporter struct {
 	enableLockWaitTimeout bool
 	lockWaitTimeout       int
 	slowLogFilter         bool
+	privilegeCheck        bool
+	deadlineMargin        time.Duration
+	backoff               *CollectorBackoff
+	loadShedding          LoadShedding
+	sessionGuardrails     SessionGuardrails
+	resultHandler         func(ScrapeResult)
+	naming                MetricsNaming
+	seriesLimits          SeriesLimits
+	metricRules           MetricRules
+	queryComments         bool
+	tracer                trace.Tracer
+	tracing               bool
+	recordSnapshot        *Snapshot
+	replaySnapshot        *Snapshot
+}
+
+// ScrapeResult describes the outcome of a single scrape of a target.
+type ScrapeResult struct {
+	Target   string
+	Version  string
+	Flavor   string
+	Time     time.Time
+	Duration time.Duration
+	Err      error
+	// Incomplete is set if the scrape ran out of time.
+	Incomplete bool
+	Collectors []CollectorResult
+}
+
+// CollectorResult describes the outcome of a single scraper run.
+type CollectorResult struct {
+	Name     string
+	Duration time.Duration
+	Series   int
+	// Dropped is the number of series dropped or folded by the series limit.
+	Dropped int
+	Err     error
+	// TimedOut is set if the scraper did not complete before the deadline.
+	TimedOut bool
+	// Suspended is set if the scraper was skipped or failed because of a
+	// permanent error. Err is the permanent error.
+	Suspended bool
+	// Shed is set if the scraper was skipped because the server was under
+	// load.
+	Shed bool
 }
 
 type ExporterOpt func(*Exporter)
@@ -96,12 +155,37 @@ func SetSlowLogFilter(b bool) ExporterOpt {
 	}
 }
 
+// EnablePrivilegeCheck compares SHOW GRANTS against the privileges required by
+// the scrapers on every scrape.
+func EnablePrivilegeCheck(b bool) ExporterOpt {
+	return func(e *Exporter) {
+		e.privilegeCheck = b
+	}
+}
+
+// SetDeadlineMargin stops scrapers this long before the deadline of the scrape
+// context, leaving time to send the metrics collected so far. The margin is
+// ignored if it exceeds half of the time left for the scrape.
+func SetDeadlineMargin(margin time.Duration) ExporterOpt {
+	return func(e *Exporter) {
+		e.deadlineMargin = margin
+	}
+}
+
+// SetResultHandler registers a function called with the result of every scrape.
+func SetResultHandler(fn func(ScrapeResult)) ExporterOpt {
+	return func(e *Exporter) {
+		e.resultHandler = fn
+	}
+}
+
 // New returns a new MySQL exporter for the provided DSN.
 func New(ctx context.Context, dsn string, scrapers []Scraper, logger *slog.Logger, opts ...ExporterOpt) *Exporter {
 	e := &Exporter{
 		ctx:      ctx,
 		logger:   logger,
 		scrapers: scrapers,
+		tracer:   noopTracer,
 	}
 
 	for _, opt := range opts {
@@ -137,10 +221,42 @@ func (e *Exporter) Describe(ch chan<- *prometheus.Desc) {
 	ch <- mysqlUp
 	ch <- mysqlScrapeDurationSeconds
 	ch <- mysqlScrapeCollectorSuccess
+	ch <- mysqlScrapeCollectorTimedOut
+	ch <- scrapeIncompleteDesc
+	if e.privilegeCheck {
+		ch <- missingPrivilegeDesc
+	}
+	if e.sessionGuardrails.enabled() {
+		ch <- sessionGuardrailDesc
+	}
+	if e.backoff != nil {
+		ch <- collectorSuspendedDesc
+	}
+	if e.loadShedding.enabled() {
+		ch <- collectorShedDesc
+		ch <- loadSheddingSignalDesc
+	}
+	if e.seriesLimits.enabled() {
+		ch <- seriesDroppedDesc
+	}
 }
 
 // Collect implements prometheus.Collector.
 func (e *Exporter) Collect(ch chan<- prometheus.Metric) {
+	if !e.metricRules.empty() {
+		out := ch
+		in := make(chan prometheus.Metric)
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			e.metricRules.apply(in, out, e.logger)
+		}()
+		defer func() {
+			close(in)
+			<-done
+		}()
+		ch = in
+	}
 	up := e.scrape(e.ctx, ch)
 	ch <- prometheus.MustNewConstMetric(mysqlUp, prometheus.GaugeValue, up)
 }
@@ -149,43 +265,277 @@ func (e *Exporter) Collect(ch chan<- prometheus.Metric) {
 func (e *Exporter) scrape(ctx context.Context, ch chan<- prometheus.Metric) float64 {
 	var err error
 	scrapeTime := time.Now()
-	instance, err := newInstance(e.dsn)
-	if err != nil {
-		e.logger.Error("Error opening connection to database", "err", err)
-		return 0.0
+	result := ScrapeResult{
+		Target: e.getTargetFromDsn(),
+		Time:   scrapeTime,
+	}
+	if e.resultHandler != nil {
+		defer func() {
+			result.Duration = time.Since(scrapeTime)
+			e.resultHandler(result)
+		}()
 	}
-	defer instance.Close()
-	e.instance = instance
 
-	if err := instance.Ping(); err != nil {
-		e.logger.Error("Error pinging mysqld", "err", err)
+	// Stop the scrape a margin before the deadline, so that the metrics
+	// collected so far can still be sent. A margin taking more than half of
+	// the remaining time would leave too little to the collectors.
+	if deadline, ok := ctx.Deadline(); ok && e.deadlineMargin > 0 && e.deadlineMargin <= time.Until(deadline)/2 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, deadline.Add(-e.deadlineMargin))
+		defer cancel()
+	}
+	defer func() {
+		ch <- prometheus.MustNewConstMetric(scrapeIncompleteDesc, prometheus.GaugeValue, boolToFloat64(result.Incomplete))
+	}()
+
+	instance, err := e.connect(ctx)
+	if err != nil {
+		result.Err = err
+		result.Incomplete = ctx.Err() != nil
 		return 0.0
 	}
+	e.instance = instance
+	result.Version = instance.version.String()
+	result.Flavor = instance.flavor
 
 	ch <- prometheus.MustNewConstMetric(mysqlScrapeDurationSeconds, prometheus.GaugeValue, time.Since(scrapeTime).Seconds(), "connection")
 
+	if e.sessionGuardrails.enabled() {
+		applySessionGuardrails(ctx, instance, e.sessionGuardrails, ch, e.logger.With("target", result.Target))
+	}
+
 	version := instance.versionMajorMinor
 
-	var wg sync.WaitGroup
-	defer wg.Wait()
+	if e.privilegeCheck {
+		var scrapers []Scraper
+		for _, scraper := range e.scrapers {
+			if version >= scraper.Version() {
+				scrapers = append(scrapers, scraper)
+			}
+		}
+		if err := checkPrivileges(ctx, instance, scrapers, ch); err != nil {
+			e.logger.Warn("Error checking privileges", "target", result.Target, "err", err)
+		}
+	}
+
+	var shed error
+	if e.loadShedding.enabled() {
+		overloaded, value, err := e.loadShedding.overloaded(ctx, instance, ch)
+		switch {
+		case err != nil:
+			e.logger.Warn("Error reading load shedding signal", "target", result.Target, "err", err)
+		case overloaded:
+			shed = fmt.Errorf("skipped while %s is %g, above %g", e.loadShedding.Variable, value, e.loadShedding.Threshold)
+			e.logger.Debug("Skipping expensive collectors", "target", result.Target, "variable", e.loadShedding.Variable, "value", value)
+		}
+	}
+
+	// Scrapers send their metrics and results to this goroutine, which
+	// forwards them until all scrapers are done or the deadline is reached.
+	var (
+		wg      sync.WaitGroup
+		metrics = make(chan prometheus.Metric)
+		results = make(chan CollectorResult)
+		running = map[string]time.Time{}
+	)
 	for _, scraper := range e.scrapers {
 		if version < scraper.Version() {
 			continue
 		}
-
+		if shed != nil && CostOf(scraper) == CostExpensive {
+			r := CollectorResult{Name: scraper.Name(), Err: shed, Shed: true}
+			e.sendCollectorResult(ch, r, result.Target)
+			result.Collectors = append(result.Collectors, r)
+			continue
+		}
+		if e.backoff != nil {
+			if err := e.backoff.check(e.backoffTarget(), scraper.Name()); err != nil {
+				r := CollectorResult{Name: scraper.Name(), Err: err, Suspended: true}
+				e.sendCollectorResult(ch, r, result.Target)
+				result.Collectors = append(result.Collectors, r)
+				continue
+			}
+		}
+		running[scraper.Name()] = time.Now()
 		wg.Go(func() {
-			label := "collect." + scraper.Name()
 			scrapeTime := time.Now()
-			collectorSuccess := 1.0
-			if err := scraper.Scrape(ctx, instance, ch, e.logger.With("scraper", scraper.Name())); err != nil {
-				e.logger.Error("Error from scraper", "scraper", scraper.Name(), "target", e.getTargetFromDsn(), "err", err)
-				collectorSuccess = 0.0
+			series, dropped, err := e.runScraper(ctx, scraper, instance, metrics)
+			results <- CollectorResult{
+				Name:     scraper.Name(),
+				Duration: time.Since(scrapeTime),
+				Series:   series,
+				Dropped:  dropped,
+				Err:      err,
+				TimedOut: err != nil && ctx.Err() != nil,
 			}
-			ch <- prometheus.MustNewConstMetric(mysqlScrapeCollectorSuccess, prometheus.GaugeValue, collectorSuccess, label)
-			ch <- prometheus.MustNewConstMetric(mysqlScrapeDurationSeconds, prometheus.GaugeValue, time.Since(scrapeTime).Seconds(), label)
 		})
 	}
-	return 1.0
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	for {
+		select {
+		case m := <-metrics:
+			ch <- m
+		case r := <-results:
+			delete(running, r.Name)
+			if e.backoff != nil {
+				if suspension := e.backoff.record(e.backoffTarget(), r.Name, r.Err); suspension > 0 {
+					r.Suspended = true
+					e.logger.Warn("Suspending collector after permanent error", "scraper", r.Name, "target", result.Target, "suspension", suspension, "err", r.Err)
+				}
+			}
+			e.sendCollectorResult(ch, r, result.Target)
+			result.Collectors = append(result.Collectors, r)
+		case <-done:
+			instance.Close()
+			return 1.0
+		case <-ctx.Done():
+			// Report the scrapers still running as timed out, and discard
+			// whatever they send until they noticed the cancellation.
+			result.Incomplete = true
+			for name, start := range running {
+				r := CollectorResult{Name: name, Duration: time.Since(start), Err: ctx.Err(), TimedOut: true}
+				e.sendCollectorResult(ch, r, result.Target)
+				result.Collectors = append(result.Collectors, r)
+			}
+			go func() {
+				defer instance.Close()
+				for {
+					select {
+					case <-metrics:
+					case <-results:
+					case <-done:
+						return
+					}
+				}
+			}()
+			return 1.0
+		}
+	}
+}
+
+// sendCollectorResult sends the metrics describing the run of a scraper.
+func (e *Exporter) sendCollectorResult(ch chan<- prometheus.Metric, r CollectorResult, target string) {
+	label := "collect." + r.Name
+	collectorSuccess := 1.0
+	if r.Err != nil {
+		if !r.Suspended && !r.Shed {
+			e.logger.Error("Error from scraper", "scraper", r.Name, "target", target, "err", r.Err)
+		}
+		collectorSuccess = 0.0
+	}
+	ch <- prometheus.MustNewConstMetric(mysqlScrapeCollectorSuccess, prometheus.GaugeValue, collectorSuccess, label)
+	ch <- prometheus.MustNewConstMetric(mysqlScrapeDurationSeconds, prometheus.GaugeValue, r.Duration.Seconds(), label)
+	ch <- prometheus.MustNewConstMetric(mysqlScrapeCollectorTimedOut, prometheus.GaugeValue, boolToFloat64(r.TimedOut), label)
+	if e.backoff != nil {
+		ch <- prometheus.MustNewConstMetric(collectorSuspendedDesc, prometheus.GaugeValue, boolToFloat64(r.Suspended), label)
+	}
+	if e.loadShedding.enabled() {
+		ch <- prometheus.MustNewConstMetric(collectorShedDesc, prometheus.GaugeValue, boolToFloat64(r.Shed), label)
+	}
+	if e.seriesLimits.limit(r.Name) > 0 {
+		ch <- prometheus.MustNewConstMetric(seriesDroppedDesc, prometheus.GaugeValue, float64(r.Dropped), label)
+	}
+	if r.Dropped > 0 {
+		e.logger.Debug("Series limit reached", "scraper", r.Name, "target", target, "dropped", r.Dropped)
+	}
+}
+
+// connect opens a connection to the target and checks that it responds.
+func (e *Exporter) connect(ctx context.Context) (_ *instance, err error) {
+	ctx, span := e.tracer.Start(ctx, "connect", trace.WithAttributes(attrServerAddr.String(e.getTargetFromDsn())))
+	defer func() { endSpan(span, err) }()
+
+	connector, err := e.connector()
+	if err != nil {
+		e.logger.Error("Error opening connection to database", "err", err)
+		return nil, err
+	}
+	instance, err := newInstance(ctx, connector)
+	if err != nil {
+		e.logger.Error("Error opening connection to database", "err", err)
+		return nil, err
+	}
+	if err := instance.Ping(ctx); err != nil {
+		e.logger.Error("Error pinging mysqld", "err", err)
+		return nil, err
+	}
+	span.SetAttributes(attribute.String("mysql.version", instance.version.String()), attribute.String("mysql.flavor", instance.flavor))
+	return instance, nil
+}
+
+// runScraper runs a single scraper, forwarding its metrics to ch. It returns
+// the number of series sent and the number of series dropped by the series
+// limit.
+func (e *Exporter) runScraper(ctx context.Context, scraper Scraper, instance *instance, ch chan<- prometheus.Metric) (series, dropped int, err error) {
+	var rows atomic.Int64
+	ctx = withRowCounter(withCollectorName(ctx, scraper.Name()), &rows)
+	ctx, span := e.tracer.Start(ctx, "scrape "+scraper.Name(), trace.WithAttributes(attrCollector.String(scraper.Name())))
+	defer func() {
+		span.SetAttributes(attrReturnedRows.Int64(rows.Load()), attribute.Int("mysql.series", series))
+		endSpan(span, err)
+	}()
+
+	scraperCh := make(chan prometheus.Metric)
+	limiter := newSeriesLimiter(e.seriesLimits.limit(scraper.Name()), e.seriesLimits.Fold)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for m := range scraperCh {
+			if e.naming == NamingV2 {
+				var err error
+				if m, err = renameV2(m); err != nil {
+					e.logger.Error("Error renaming metric", "scraper", scraper.Name(), "err", err)
+					continue
+				}
+			}
+			if limiter.admit(m) {
+				ch <- m
+			}
+		}
+	}()
+	err = scraper.Scrape(ctx, instance, scraperCh, e.logger.With("scraper", scraper.Name()))
+	close(scraperCh)
+	<-done
+	return limiter.series + limiter.flush(ch), limiter.dropped, err
+}
+
+// connector returns the connector for the target, wrapped to comment, record
+// or trace queries as configured, or replaying a snapshot if set.
+func (e *Exporter) connector() (driver.Connector, error) {
+	if e.replaySnapshot != nil {
+		return e.traced(replayConnector{snapshot: e.replaySnapshot}), nil
+	}
+	cfg, err := mysql.ParseDSN(e.dsn)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.Contains(cfg.ConnectionAttributes, "program_name:") {
+		cfg.ConnectionAttributes = strings.TrimPrefix(cfg.ConnectionAttributes+",program_name:"+programName, ",")
+	}
+	connector, err := mysql.NewConnector(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if e.queryComments {
+		connector = commentingConnector{connector}
+	}
+	if e.recordSnapshot != nil {
+		connector = recordingConnector{Connector: connector, snapshot: e.recordSnapshot}
+	}
+	return e.traced(connector), nil
+}
+
+// traced returns the connector tracing queries if tracing is enabled.
+func (e *Exporter) traced(connector driver.Connector) driver.Connector {
+	if !e.tracing {
+		return connector
+	}
+	return tracingConnector{Connector: connector, tracer: e.tracer}
 }
 
 func (e *Exporter) getTargetFromDsn() string {
@@ -197,3 +547,20 @@ func (e *Exporter) getTargetFromDsn() string {
 	}
 	return dsnConfig.Addr
 }
+
+// backoffTarget identifies the target and user in the collector backoff, as
+// users may differ in privileges.
+func (e *Exporter) backoffTarget() string {
+	dsnConfig, err := mysql.ParseDSN(e.dsn)
+	if err != nil {
+		return ""
+	}
+	return dsnConfig.User + "@" + dsnConfig.Addr
+}
+
+func boolToFloat64(b bool) float64 {
+	if b {
+		return 1.0
+	}
+	return 0.0
+}
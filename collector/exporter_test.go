@@ -15,10 +15,17 @@ package collector
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"log/slog"
 	"os"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
 	"github.com/prometheus/common/promslog"
 	"github.com/smartystreets/goconvey/convey"
 )
@@ -146,3 +153,196 @@ func TestExporterWithOpts(t *testing.T) {
 		})
 	})
 }
+
+func TestExporterRunScraper(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error opening a stub database connection: %s", err)
+	}
+	defer db.Close()
+	inst := &instance{db: db}
+
+	rows := sqlmock.NewRows([]string{"Variable_name", "Value"}).
+		AddRow("Com_select", "1").
+		AddRow("Uptime", "10")
+	mock.ExpectQuery(sanitizeQuery(globalStatusQuery)).WillReturnRows(rows)
+
+	exporter := New(context.Background(), dsn, nil, promslog.NewNopLogger())
+
+	convey.Convey("Series are counted and forwarded", t, func() {
+		ch := make(chan prometheus.Metric)
+		go func() {
+			for range ch {
+			}
+		}()
+		series, dropped, err := exporter.runScraper(context.Background(), ScrapeGlobalStatus{}, inst, ch)
+		close(ch)
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(series, convey.ShouldEqual, 2)
+		convey.So(dropped, convey.ShouldEqual, 0)
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled exceptions: %s", err)
+	}
+}
+
+func TestExporterRunScraperSeriesLimit(t *testing.T) {
+	for _, fold := range []bool{false, true} {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("error opening a stub database connection: %s", err)
+		}
+		inst := &instance{db: db}
+
+		rows := sqlmock.NewRows([]string{"Variable_name", "Value"}).
+			AddRow("Com_select", "1").
+			AddRow("Com_insert", "2").
+			AddRow("Com_update", "3")
+		mock.ExpectQuery(sanitizeQuery(globalStatusQuery)).WillReturnRows(rows)
+
+		exporter := New(context.Background(), dsn, nil, promslog.NewNopLogger(), SetSeriesLimits(SeriesLimits{
+			Default:    10,
+			Collectors: map[string]int{"global_status": 1},
+			Fold:       fold,
+		}))
+
+		convey.Convey(fmt.Sprintf("Series over the limit are dropped (fold: %t)", fold), t, func() {
+			ch := make(chan prometheus.Metric)
+			var got []MetricResult
+			received := make(chan struct{})
+			go func() {
+				for m := range ch {
+					got = append(got, readMetric(m))
+				}
+				close(received)
+			}()
+			series, dropped, err := exporter.runScraper(context.Background(), ScrapeGlobalStatus{}, inst, ch)
+			close(ch)
+			<-received
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(dropped, convey.ShouldEqual, 2)
+
+			expected := []MetricResult{
+				{labels: labelMap{"command": "select"}, value: 1, metricType: dto.MetricType_COUNTER},
+			}
+			if fold {
+				expected = append(expected, MetricResult{labels: labelMap{"command": "other"}, value: 5, metricType: dto.MetricType_COUNTER})
+			}
+			convey.So(series, convey.ShouldEqual, len(expected))
+			convey.So(got, convey.ShouldResemble, expected)
+		})
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled exceptions: %s", err)
+		}
+		db.Close()
+	}
+}
+
+func TestSeriesLimiterUnknownDescriptor(t *testing.T) {
+	convey.Convey("Metrics with an unknown descriptor are not folded", t, func() {
+		err := errors.New("invalid")
+		l := newSeriesLimiter(1, true)
+		convey.So(l.admit(prometheus.MustNewConstMetric(mysqlUp, prometheus.GaugeValue, 1)), convey.ShouldBeTrue)
+		convey.So(l.admit(prometheus.NewInvalidMetric(prometheus.NewInvalidDesc(err), err)), convey.ShouldBeTrue)
+		convey.So(l.dropped, convey.ShouldEqual, 0)
+		convey.So(l.folded, convey.ShouldBeEmpty)
+	})
+}
+
+// stuckScraper sends a metric and then ignores cancellation until released.
+type stuckScraper struct {
+	release chan struct{}
+}
+
+func (stuckScraper) Name() string     { return "stuck" }
+func (stuckScraper) Help() string     { return "" }
+func (stuckScraper) Version() float64 { return 5.1 }
+
+func (s stuckScraper) Scrape(_ context.Context, _ *instance, ch chan<- prometheus.Metric, _ *slog.Logger) error {
+	ch <- prometheus.MustNewConstMetric(newDesc("", "stuck", ""), prometheus.GaugeValue, 1)
+	<-s.release
+	ch <- prometheus.MustNewConstMetric(newDesc("", "stuck_late", ""), prometheus.GaugeValue, 1)
+	return context.DeadlineExceeded
+}
+
+func TestExporterDeadline(t *testing.T) {
+	value := func(s string) *string { return &s }
+	snapshot := &Snapshot{Queries: []SnapshotQuery{
+		{Query: "SELECT @@version;", Columns: []SnapshotColumn{{Name: "@@version"}}, Rows: [][]*string{{value("8.0.36")}}},
+		{
+			Query:   globalStatusQuery,
+			Columns: []SnapshotColumn{{Name: "Variable_name"}, {Name: "Value"}},
+			Rows:    [][]*string{{value("Uptime"), value("10")}},
+		},
+	}}
+	stuck := stuckScraper{release: make(chan struct{})}
+	defer close(stuck.release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	var result ScrapeResult
+	exporter := New(ctx, dsn, []Scraper{ScrapeGlobalStatus{}, stuck}, promslog.NewNopLogger(),
+		ReplaySnapshot(snapshot), SetDeadlineMargin(100*time.Millisecond), SetResultHandler(func(r ScrapeResult) { result = r }))
+
+	convey.Convey("Scrapes return the metrics collected before the deadline", t, func() {
+		ch := make(chan prometheus.Metric)
+		go func() {
+			exporter.Collect(ch)
+			close(ch)
+		}()
+		var metrics []prometheus.Metric
+		for m := range ch {
+			metrics = append(metrics, m)
+		}
+		convey.So(ctx.Err(), convey.ShouldBeNil)
+
+		// value returns the value of the metric whose name or descriptor and
+		// label values match.
+		value := func(desc any, labels ...string) float64 {
+			for _, m := range metrics {
+				got := readMetric(m)
+				switch d := desc.(type) {
+				case *prometheus.Desc:
+					if m.Desc() != d {
+						continue
+					}
+				case string:
+					if !strings.Contains(m.Desc().String(), `"`+d+`"`) {
+						continue
+					}
+				}
+				if len(labels) == 0 || got.labels["collector"] == labels[0] {
+					return got.value
+				}
+			}
+			return -1
+		}
+		convey.So(value(scrapeIncompleteDesc), convey.ShouldEqual, 1)
+		convey.So(value(mysqlScrapeCollectorTimedOut, "collect.stuck"), convey.ShouldEqual, 1)
+		convey.So(value(mysqlScrapeCollectorSuccess, "collect.stuck"), convey.ShouldEqual, 0)
+		convey.So(value(mysqlScrapeCollectorTimedOut, "collect.global_status"), convey.ShouldEqual, 0)
+		convey.So(value("mysql_stuck"), convey.ShouldEqual, 1)
+		convey.So(value("mysql_stuck_late"), convey.ShouldEqual, -1)
+		convey.So(value("mysql_global_status_uptime"), convey.ShouldEqual, 10)
+
+		convey.So(result.Incomplete, convey.ShouldBeTrue)
+		convey.So(result.Collectors, convey.ShouldHaveLength, 2)
+	})
+	convey.Convey("Margins exceeding half of the remaining time are ignored", t, func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+		defer cancel()
+		exporter := New(ctx, dsn, []Scraper{ScrapeGlobalStatus{}}, promslog.NewNopLogger(),
+			ReplaySnapshot(snapshot), SetDeadlineMargin(time.Second), SetResultHandler(func(r ScrapeResult) { result = r }))
+		ch := make(chan prometheus.Metric)
+		go func() {
+			exporter.Collect(ch)
+			close(ch)
+		}()
+		for range ch {
+		}
+		convey.So(result.Err, convey.ShouldBeNil)
+		convey.So(result.Incomplete, convey.ShouldBeFalse)
+	})
+}
@@ -98,6 +98,11 @@ func (ScrapeGlobalStatus) Version() float64 {
 	return 5.1
 }
 
+// Privileges required by the Scraper.
+func (ScrapeGlobalStatus) Privileges() []Privilege {
+	return nil
+}
+
 // Scrape collects data from database connection and sends it over channel as prometheus metric.
 func (ScrapeGlobalStatus) Scrape(ctx context.Context, instance *instance, ch chan<- prometheus.Metric, logger *slog.Logger) error {
 	db := instance.getDB()
@@ -125,8 +130,8 @@ func (ScrapeGlobalStatus) Scrape(ctx context.Context, instance *instance, ch cha
 			match := globalStatusRE.FindStringSubmatch(key)
 			if match == nil {
 				ch <- prometheus.MustNewConstMetric(
-					newDesc(globalStatus, key, "Generic metric from SHOW GLOBAL STATUS."),
-					prometheus.UntypedValue,
+					newDesc(globalStatus, key, globalStatusCatalog[key].help("Generic metric from SHOW GLOBAL STATUS.")),
+					globalStatusType(key),
 					floatVal,
 				)
 				continue
@@ -227,4 +232,4 @@ func (ScrapeGlobalStatus) Scrape(ctx context.Context, instance *instance, ch cha
 }
 
 // check interface
-var _ Scraper = ScrapeGlobalStatus{}
+var _ PrivilegedScraper = ScrapeGlobalStatus{}
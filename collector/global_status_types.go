@@ -0,0 +1,368 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import "github.com/prometheus/client_golang/prometheus"
+
+const (
+	counter = prometheus.CounterValue
+	gauge   = prometheus.GaugeValue
+)
+
+// globalStatusTypes classifies SHOW GLOBAL STATUS variables as counters or
+// gauges. Keys are the sanitized lower case variable names. Variables not
+// listed here, and those handled by globalStatusRE, are not looked up.
+//
+// See:
+// https://dev.mysql.com/doc/refman/8.4/en/server-status-variables.html
+// https://mariadb.com/kb/en/server-status-variables/
+// https://docs.percona.com/percona-server/8.0/status-variables.html
+// https://galeracluster.com/library/documentation/galera-status-variables.html
+var globalStatusTypes = map[string]prometheus.ValueType{
+	// Connections and threads.
+	"aborted_clients":                    counter,
+	"aborted_connects":                   counter,
+	"aborted_connects_preauth":           counter,
+	"access_denied_errors":               counter,
+	"connections":                        counter,
+	"locked_connects":                    counter,
+	"max_used_connections":               gauge,
+	"max_used_connections_time":          gauge,
+	"threads_cached":                     gauge,
+	"threads_connected":                  gauge,
+	"threads_created":                    counter,
+	"threads_running":                    gauge,
+	"slow_launch_threads":                counter,
+	"delayed_insert_threads":             gauge,
+	"delayed_errors":                     counter,
+	"delayed_writes":                     counter,
+	"not_flushed_delayed_rows":           gauge,
+	"global_connection_memory":           gauge,
+	"memory_used":                        gauge,
+	"memory_used_initial":                gauge,
+	"threadpool_idle_threads":            gauge,
+	"threadpool_threads":                 gauge,
+	"connection_control_delay_generated": counter,
+
+	// Traffic and statements.
+	"bytes_received":                      counter,
+	"bytes_sent":                          counter,
+	"queries":                             counter,
+	"questions":                           counter,
+	"slow_queries":                        counter,
+	"empty_queries":                       counter,
+	"busy_time":                           counter,
+	"cpu_time":                            counter,
+	"rows_read":                           counter,
+	"rows_sent":                           counter,
+	"rows_tmp_read":                       counter,
+	"max_execution_time_exceeded":         counter,
+	"max_execution_time_set":              counter,
+	"max_execution_time_set_failed":       counter,
+	"max_statement_time_exceeded":         counter,
+	"executed_events":                     counter,
+	"executed_triggers":                   counter,
+	"prepared_stmt_count":                 gauge,
+	"secondary_engine_execution_count":    counter,
+	"flush_commands":                      counter,
+	"ongoing_anonymous_transaction_count": gauge,
+	"uptime":                              gauge,
+	"uptime_since_flush_status":           gauge,
+
+	// Query execution.
+	"created_tmp_disk_tables":   counter,
+	"created_tmp_files":         counter,
+	"created_tmp_tables":        counter,
+	"select_full_join":          counter,
+	"select_full_range_join":    counter,
+	"select_range":              counter,
+	"select_range_check":        counter,
+	"select_scan":               counter,
+	"sort_merge_passes":         counter,
+	"sort_priority_queue_sorts": counter,
+	"sort_range":                counter,
+	"sort_rows":                 counter,
+	"sort_scan":                 counter,
+
+	// Tables and files.
+	"open_files":                        gauge,
+	"open_streams":                      gauge,
+	"open_table_definitions":            gauge,
+	"open_tables":                       gauge,
+	"opened_files":                      counter,
+	"opened_table_definitions":          counter,
+	"opened_tables":                     counter,
+	"opened_views":                      counter,
+	"table_locks_immediate":             counter,
+	"table_locks_waited":                counter,
+	"table_open_cache_active_instances": gauge,
+	"table_open_cache_hits":             counter,
+	"table_open_cache_misses":           counter,
+	"table_open_cache_overflows":        counter,
+
+	// Binary log.
+	"binlog_bytes_written":                  counter,
+	"binlog_cache_disk_use":                 counter,
+	"binlog_cache_use":                      counter,
+	"binlog_commits":                        counter,
+	"binlog_group_commits":                  counter,
+	"binlog_group_commit_trigger_count":     counter,
+	"binlog_group_commit_trigger_lock_wait": counter,
+	"binlog_group_commit_trigger_timeout":   counter,
+	"binlog_snapshot_position":              gauge,
+	"binlog_stmt_cache_disk_use":            counter,
+	"binlog_stmt_cache_use":                 counter,
+	"tc_log_max_pages_used":                 gauge,
+	"tc_log_page_size":                      gauge,
+	"tc_log_page_waits":                     counter,
+
+	// Replication.
+	"slave_running":                              gauge,
+	"slave_open_temp_tables":                     gauge,
+	"replica_open_temp_tables":                   gauge,
+	"slave_connections":                          counter,
+	"slave_heartbeat_period":                     gauge,
+	"slave_received_heartbeats":                  counter,
+	"slave_retried_transactions":                 counter,
+	"slaves_connected":                           gauge,
+	"slaves_running":                             gauge,
+	"rpl_semi_sync_master_clients":               gauge,
+	"rpl_semi_sync_master_net_avg_wait_time":     gauge,
+	"rpl_semi_sync_master_net_wait_time":         counter,
+	"rpl_semi_sync_master_net_waits":             counter,
+	"rpl_semi_sync_master_no_times":              counter,
+	"rpl_semi_sync_master_no_tx":                 counter,
+	"rpl_semi_sync_master_status":                gauge,
+	"rpl_semi_sync_master_timefunc_failures":     counter,
+	"rpl_semi_sync_master_tx_avg_wait_time":      gauge,
+	"rpl_semi_sync_master_tx_wait_time":          counter,
+	"rpl_semi_sync_master_tx_waits":              counter,
+	"rpl_semi_sync_master_wait_pos_backtraverse": counter,
+	"rpl_semi_sync_master_wait_sessions":         gauge,
+	"rpl_semi_sync_master_yes_tx":                counter,
+	"rpl_semi_sync_slave_status":                 gauge,
+	"rpl_semi_sync_source_clients":               gauge,
+	"rpl_semi_sync_source_net_avg_wait_time":     gauge,
+	"rpl_semi_sync_source_net_wait_time":         counter,
+	"rpl_semi_sync_source_net_waits":             counter,
+	"rpl_semi_sync_source_no_times":              counter,
+	"rpl_semi_sync_source_no_tx":                 counter,
+	"rpl_semi_sync_source_status":                gauge,
+	"rpl_semi_sync_source_timefunc_failures":     counter,
+	"rpl_semi_sync_source_tx_avg_wait_time":      gauge,
+	"rpl_semi_sync_source_tx_wait_time":          counter,
+	"rpl_semi_sync_source_tx_waits":              counter,
+	"rpl_semi_sync_source_wait_pos_backtraverse": counter,
+	"rpl_semi_sync_source_wait_sessions":         gauge,
+	"rpl_semi_sync_source_yes_tx":                counter,
+	"rpl_semi_sync_replica_status":               gauge,
+
+	// Key cache.
+	"key_blocks_not_flushed": gauge,
+	"key_blocks_unused":      gauge,
+	"key_blocks_used":        gauge,
+	"key_blocks_warm":        gauge,
+	"key_read_requests":      counter,
+	"key_reads":              counter,
+	"key_write_requests":     counter,
+	"key_writes":             counter,
+
+	// Query cache.
+	"qcache_free_blocks":      gauge,
+	"qcache_free_memory":      gauge,
+	"qcache_hits":             counter,
+	"qcache_inserts":          counter,
+	"qcache_lowmem_prunes":    counter,
+	"qcache_not_cached":       counter,
+	"qcache_queries_in_cache": gauge,
+	"qcache_total_blocks":     gauge,
+
+	// InnoDB.
+	"innodb_buffer_pool_bytes_data":         gauge,
+	"innodb_buffer_pool_bytes_dirty":        gauge,
+	"innodb_buffer_pool_read_ahead":         counter,
+	"innodb_buffer_pool_read_ahead_evicted": counter,
+	"innodb_buffer_pool_read_ahead_rnd":     counter,
+	"innodb_buffer_pool_read_requests":      counter,
+	"innodb_buffer_pool_reads":              counter,
+	"innodb_buffer_pool_wait_free":          counter,
+	"innodb_buffer_pool_write_requests":     counter,
+	"innodb_checkpoint_age":                 gauge,
+	"innodb_checkpoint_max_age":             gauge,
+	"innodb_data_fsyncs":                    counter,
+	"innodb_data_pending_fsyncs":            gauge,
+	"innodb_data_pending_reads":             gauge,
+	"innodb_data_pending_writes":            gauge,
+	"innodb_data_read":                      counter,
+	"innodb_data_reads":                     counter,
+	"innodb_data_writes":                    counter,
+	"innodb_data_written":                   counter,
+	"innodb_dblwr_pages_written":            counter,
+	"innodb_dblwr_writes":                   counter,
+	"innodb_deadlocks":                      counter,
+	"innodb_history_list_length":            gauge,
+	"innodb_ibuf_free_list":                 gauge,
+	"innodb_ibuf_merged_delete_marks":       counter,
+	"innodb_ibuf_merged_deletes":            counter,
+	"innodb_ibuf_merged_inserts":            counter,
+	"innodb_ibuf_merges":                    counter,
+	"innodb_ibuf_segment_size":              gauge,
+	"innodb_ibuf_size":                      gauge,
+	"innodb_log_waits":                      counter,
+	"innodb_log_write_requests":             counter,
+	"innodb_log_writes":                     counter,
+	"innodb_lsn_current":                    counter,
+	"innodb_lsn_flushed":                    counter,
+	"innodb_lsn_last_checkpoint":            counter,
+	"innodb_mem_adaptive_hash":              gauge,
+	"innodb_mem_dictionary":                 gauge,
+	"innodb_num_open_files":                 gauge,
+	"innodb_os_log_fsyncs":                  counter,
+	"innodb_os_log_pending_fsyncs":          gauge,
+	"innodb_os_log_pending_writes":          gauge,
+	"innodb_os_log_written":                 counter,
+	"innodb_page_size":                      gauge,
+	"innodb_pages_created":                  counter,
+	"innodb_pages_read":                     counter,
+	"innodb_pages_written":                  counter,
+	"innodb_redo_log_capacity_resized":      gauge,
+	"innodb_redo_log_checkpoint_lsn":        counter,
+	"innodb_redo_log_current_lsn":           counter,
+	"innodb_redo_log_enabled":               gauge,
+	"innodb_redo_log_flushed_to_disk_lsn":   counter,
+	"innodb_redo_log_logical_size":          gauge,
+	"innodb_redo_log_physical_size":         gauge,
+	"innodb_redo_log_read_only":             gauge,
+	"innodb_row_lock_current_waits":         gauge,
+	"innodb_row_lock_time":                  counter,
+	"innodb_row_lock_time_avg":              gauge,
+	"innodb_row_lock_time_max":              gauge,
+	"innodb_row_lock_waits":                 counter,
+	"innodb_sampled_pages_read":             counter,
+	"innodb_sampled_pages_skipped":          counter,
+	"innodb_system_rows_deleted":            counter,
+	"innodb_system_rows_inserted":           counter,
+	"innodb_system_rows_read":               counter,
+	"innodb_system_rows_updated":            counter,
+	"innodb_truncated_status_writes":        counter,
+	"innodb_undo_tablespaces_active":        gauge,
+	"innodb_undo_tablespaces_explicit":      gauge,
+	"innodb_undo_tablespaces_implicit":      gauge,
+	"innodb_undo_tablespaces_total":         gauge,
+	"innodb_page_compression_saved":         counter,
+	"innodb_num_pages_page_compressed":      counter,
+	"innodb_num_pages_page_decompressed":    counter,
+
+	// TLS.
+	"ssl_accept_renegotiates":        counter,
+	"ssl_accepts":                    counter,
+	"ssl_callback_cache_hits":        counter,
+	"ssl_client_connects":            counter,
+	"ssl_connect_renegotiates":       counter,
+	"ssl_finished_accepts":           counter,
+	"ssl_finished_connects":          counter,
+	"ssl_session_cache_hits":         counter,
+	"ssl_session_cache_misses":       counter,
+	"ssl_session_cache_overflows":    counter,
+	"ssl_session_cache_size":         gauge,
+	"ssl_session_cache_timeouts":     counter,
+	"ssl_sessions_reused":            gauge,
+	"ssl_used_session_cache_entries": gauge,
+	"ssl_verify_depth":               gauge,
+
+	// X Plugin.
+	"mysqlx_bytes_received":           counter,
+	"mysqlx_bytes_sent":               counter,
+	"mysqlx_connection_accept_errors": counter,
+	"mysqlx_connection_errors":        counter,
+	"mysqlx_connections_accepted":     counter,
+	"mysqlx_connections_closed":       counter,
+	"mysqlx_connections_rejected":     counter,
+	"mysqlx_errors_sent":              counter,
+	"mysqlx_sessions":                 gauge,
+	"mysqlx_sessions_accepted":        counter,
+	"mysqlx_sessions_closed":          counter,
+	"mysqlx_sessions_fatal_error":     counter,
+	"mysqlx_sessions_killed":          counter,
+	"mysqlx_sessions_rejected":        counter,
+	"mysqlx_worker_threads":           gauge,
+	"mysqlx_worker_threads_active":    gauge,
+
+	// Error log and components.
+	"error_log_buffered_bytes":                      gauge,
+	"error_log_buffered_events":                     gauge,
+	"error_log_expired_events":                      counter,
+	"error_log_latest_write":                        gauge,
+	"validate_password_dictionary_file_words_count": gauge,
+
+	// Galera.
+	"wsrep_apply_oooe":             gauge,
+	"wsrep_apply_oool":             gauge,
+	"wsrep_apply_window":           gauge,
+	"wsrep_cert_deps_distance":     gauge,
+	"wsrep_cert_index_size":        gauge,
+	"wsrep_cert_interval":          gauge,
+	"wsrep_cluster_conf_id":        gauge,
+	"wsrep_cluster_size":           gauge,
+	"wsrep_cluster_status":         gauge,
+	"wsrep_commit_oooe":            gauge,
+	"wsrep_commit_oool":            gauge,
+	"wsrep_commit_window":          gauge,
+	"wsrep_connected":              gauge,
+	"wsrep_desync_count":           gauge,
+	"wsrep_flow_control_active":    gauge,
+	"wsrep_flow_control_paused":    gauge,
+	"wsrep_flow_control_paused_ns": counter,
+	"wsrep_flow_control_recv":      counter,
+	"wsrep_flow_control_requested": gauge,
+	"wsrep_flow_control_sent":      counter,
+	"wsrep_last_committed":         counter,
+	"wsrep_local_bf_aborts":        counter,
+	"wsrep_local_cached_downto":    gauge,
+	"wsrep_local_cert_failures":    counter,
+	"wsrep_local_commits":          counter,
+	"wsrep_local_index":            gauge,
+	"wsrep_local_recv_queue":       gauge,
+	"wsrep_local_recv_queue_avg":   gauge,
+	"wsrep_local_recv_queue_max":   gauge,
+	"wsrep_local_recv_queue_min":   gauge,
+	"wsrep_local_replays":          counter,
+	"wsrep_local_send_queue":       gauge,
+	"wsrep_local_send_queue_avg":   gauge,
+	"wsrep_local_send_queue_max":   gauge,
+	"wsrep_local_send_queue_min":   gauge,
+	"wsrep_local_state":            gauge,
+	"wsrep_open_connections":       gauge,
+	"wsrep_open_transactions":      gauge,
+	"wsrep_protocol_version":       gauge,
+	"wsrep_ready":                  gauge,
+	"wsrep_received":               counter,
+	"wsrep_received_bytes":         counter,
+	"wsrep_repl_data_bytes":        counter,
+	"wsrep_repl_keys":              counter,
+	"wsrep_repl_keys_bytes":        counter,
+	"wsrep_repl_other_bytes":       counter,
+	"wsrep_replicated":             counter,
+	"wsrep_replicated_bytes":       counter,
+	"wsrep_thread_count":           gauge,
+}
+
+// globalStatusType returns the type of a SHOW GLOBAL STATUS variable, or
+// untyped if it is not known.
+func globalStatusType(name string) prometheus.ValueType {
+	if t, ok := globalStatusTypes[name]; ok {
+		return t
+	}
+	return prometheus.UntypedValue
+}
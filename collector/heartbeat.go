@@ -22,7 +22,6 @@ import (
 	"log/slog"
 	"strconv"
 
-	"github.com/alecthomas/kingpin/v2"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
@@ -36,21 +35,6 @@ const (
 	heartbeatQuery = "SELECT UNIX_TIMESTAMP(ts), UNIX_TIMESTAMP(%s), server_id from `%s`.`%s`"
 )
 
-var (
-	collectHeartbeatDatabase = kingpin.Flag(
-		"collect.heartbeat.database",
-		"Database from where to collect heartbeat data",
-	).Default("heartbeat").String()
-	collectHeartbeatTable = kingpin.Flag(
-		"collect.heartbeat.table",
-		"Table from where to collect heartbeat data",
-	).Default("heartbeat").String()
-	collectHeartbeatUtc = kingpin.Flag(
-		"collect.heartbeat.utc",
-		"Use UTC for timestamps of the current server (`pt-heartbeat` is called with `--utc`)",
-	).Bool()
-)
-
 // Metric descriptors.
 var (
 	HeartbeatStoredDesc = prometheus.NewDesc(
@@ -74,7 +58,32 @@ var (
 //	server_id             int unsigned NOT NULL PRIMARY KEY,
 //
 // );
-type ScrapeHeartbeat struct{}
+type ScrapeHeartbeat struct {
+	// Options of the scraper, DefaultHeartbeatOptions() if nil.
+	Options *HeartbeatOptions
+}
+
+// HeartbeatOptions configures ScrapeHeartbeat.
+type HeartbeatOptions struct {
+	// Database and Table from where to collect heartbeat data.
+	Database string
+	Table    string
+	// UTC uses UTC for timestamps of the current server (pt-heartbeat is
+	// called with --utc).
+	UTC bool
+}
+
+// DefaultHeartbeatOptions returns the default options of ScrapeHeartbeat.
+func DefaultHeartbeatOptions() HeartbeatOptions {
+	return HeartbeatOptions{Database: "heartbeat", Table: "heartbeat"}
+}
+
+func (s ScrapeHeartbeat) options() HeartbeatOptions {
+	if s.Options == nil {
+		return DefaultHeartbeatOptions()
+	}
+	return *s.Options
+}
 
 // Name of the Scraper. Should be unique.
 func (ScrapeHeartbeat) Name() string {
@@ -91,18 +100,25 @@ func (ScrapeHeartbeat) Version() float64 {
 	return 5.1
 }
 
+// Privileges required by the Scraper.
+func (s ScrapeHeartbeat) Privileges() []Privilege {
+	opts := s.options()
+	return []Privilege{{Name: "SELECT", Object: opts.Database + "." + opts.Table}}
+}
+
 // nowExpr returns a current timestamp expression.
-func nowExpr() string {
-	if *collectHeartbeatUtc {
+func nowExpr(utc bool) string {
+	if utc {
 		return "UTC_TIMESTAMP(6)"
 	}
 	return "NOW(6)"
 }
 
 // Scrape collects data from database connection and sends it over channel as prometheus metric.
-func (ScrapeHeartbeat) Scrape(ctx context.Context, instance *instance, ch chan<- prometheus.Metric, logger *slog.Logger) error {
+func (s ScrapeHeartbeat) Scrape(ctx context.Context, instance *instance, ch chan<- prometheus.Metric, logger *slog.Logger) error {
+	opts := s.options()
 	db := instance.getDB()
-	query := fmt.Sprintf(heartbeatQuery, nowExpr(), *collectHeartbeatDatabase, *collectHeartbeatTable)
+	query := fmt.Sprintf(heartbeatQuery, nowExpr(opts.UTC), opts.Database, opts.Table)
 	heartbeatRows, err := db.QueryContext(ctx, query)
 	if err != nil {
 		return err
@@ -149,4 +165,4 @@ func (ScrapeHeartbeat) Scrape(ctx context.Context, instance *instance, ch chan<-
 }
 
 // check interface
-var _ Scraper = ScrapeHeartbeat{}
+var _ PrivilegedScraper = ScrapeHeartbeat{}
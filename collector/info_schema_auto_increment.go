@@ -68,6 +68,16 @@ func (ScrapeAutoIncrementColumns) Version() float64 {
 	return 5.1
 }
 
+// Privileges required by the Scraper.
+func (ScrapeAutoIncrementColumns) Privileges() []Privilege {
+	return []Privilege{privSelectAll}
+}
+
+// Cost of the Scraper.
+func (ScrapeAutoIncrementColumns) Cost() ScraperCost {
+	return CostExpensive
+}
+
 // Scrape collects data from database connection and sends it over channel as prometheus metric.
 func (ScrapeAutoIncrementColumns) Scrape(ctx context.Context, instance *instance, ch chan<- prometheus.Metric, logger *slog.Logger) error {
 	db := instance.getDB()
@@ -101,4 +111,4 @@ func (ScrapeAutoIncrementColumns) Scrape(ctx context.Context, instance *instance
 }
 
 // check interface
-var _ Scraper = ScrapeAutoIncrementColumns{}
+var _ PrivilegedScraper = ScrapeAutoIncrementColumns{}
@@ -158,6 +158,11 @@ func (ScrapeClientStat) Version() float64 {
 	return 5.5
 }
 
+// Privileges required by the Scraper.
+func (ScrapeClientStat) Privileges() []Privilege {
+	return []Privilege{privProcess}
+}
+
 // Scrape collects data from database connection and sends it over channel as prometheus metric.
 func (ScrapeClientStat) Scrape(ctx context.Context, instance *instance, ch chan<- prometheus.Metric, logger *slog.Logger) error {
 	var varName, varVal string
@@ -220,4 +225,4 @@ func (ScrapeClientStat) Scrape(ctx context.Context, instance *instance, ch chan<
 }
 
 // check interface
-var _ Scraper = ScrapeClientStat{}
+var _ PrivilegedScraper = ScrapeClientStat{}
@@ -75,6 +75,11 @@ func (ScrapeInnodbCmp) Version() float64 {
 	return 5.5
 }
 
+// Privileges required by the Scraper.
+func (ScrapeInnodbCmp) Privileges() []Privilege {
+	return []Privilege{privProcess}
+}
+
 // Scrape collects data from database connection and sends it over channel as prometheus metric.
 func (ScrapeInnodbCmp) Scrape(ctx context.Context, instance *instance, ch chan<- prometheus.Metric, logger *slog.Logger) error {
 	db := instance.getDB()
@@ -107,4 +112,4 @@ func (ScrapeInnodbCmp) Scrape(ctx context.Context, instance *instance, ch chan<-
 }
 
 // check interface
-var _ Scraper = ScrapeInnodbCmp{}
+var _ PrivilegedScraper = ScrapeInnodbCmp{}
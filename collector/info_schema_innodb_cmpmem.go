@@ -70,6 +70,11 @@ func (ScrapeInnodbCmpMem) Version() float64 {
 	return 5.5
 }
 
+// Privileges required by the Scraper.
+func (ScrapeInnodbCmpMem) Privileges() []Privilege {
+	return []Privilege{privProcess}
+}
+
 // Scrape collects data from database connection and sends it over channel as prometheus metric.
 func (ScrapeInnodbCmpMem) Scrape(ctx context.Context, instance *instance, ch chan<- prometheus.Metric, logger *slog.Logger) error {
 	db := instance.getDB()
@@ -100,4 +105,4 @@ func (ScrapeInnodbCmpMem) Scrape(ctx context.Context, instance *instance, ch cha
 }
 
 // check interface
-var _ Scraper = ScrapeInnodbCmpMem{}
+var _ PrivilegedScraper = ScrapeInnodbCmpMem{}
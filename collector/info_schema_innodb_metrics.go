@@ -90,6 +90,11 @@ func (ScrapeInnodbMetrics) Version() float64 {
 	return 5.6
 }
 
+// Privileges required by the Scraper.
+func (ScrapeInnodbMetrics) Privileges() []Privilege {
+	return []Privilege{privProcess}
+}
+
 // Scrape collects data from database connection and sends it over channel as prometheus metric.
 func (ScrapeInnodbMetrics) Scrape(ctx context.Context, instance *instance, ch chan<- prometheus.Metric, logger *slog.Logger) error {
 	var enabledColumnName string
@@ -199,4 +204,4 @@ func (ScrapeInnodbMetrics) Scrape(ctx context.Context, instance *instance, ch ch
 }
 
 // check interface
-var _ Scraper = ScrapeInnodbMetrics{}
+var _ PrivilegedScraper = ScrapeInnodbMetrics{}
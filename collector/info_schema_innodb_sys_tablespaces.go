@@ -100,6 +100,16 @@ func (ScrapeInfoSchemaInnodbTablespaces) Version() float64 {
 	return 5.7
 }
 
+// Privileges required by the Scraper.
+func (ScrapeInfoSchemaInnodbTablespaces) Privileges() []Privilege {
+	return []Privilege{privProcess}
+}
+
+// Cost of the Scraper.
+func (ScrapeInfoSchemaInnodbTablespaces) Cost() ScraperCost {
+	return CostExpensive
+}
+
 // Scrape collects data from database connection and sends it over channel as prometheus metric.
 func (ScrapeInfoSchemaInnodbTablespaces) Scrape(ctx context.Context, instance *instance, ch chan<- prometheus.Metric, logger *slog.Logger) error {
 	var tablespacesTablename string
@@ -181,4 +191,4 @@ func (ScrapeInfoSchemaInnodbTablespaces) Scrape(ctx context.Context, instance *i
 }
 
 // check interface
-var _ Scraper = ScrapeInfoSchemaInnodbTablespaces{}
+var _ PrivilegedScraper = ScrapeInfoSchemaInnodbTablespaces{}
@@ -0,0 +1,209 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Scrape `information_schema.innodb_trx`.
+
+package collector
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+
+	"github.com/blang/semver/v4"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const innodbTrxQuery = `
+		SELECT
+		  t.trx_state,
+		  COUNT(*) AS transactions,
+		  COALESCE(SUM(p.command = 'Sleep'), 0) AS idle,
+		  COALESCE(MAX(TIMESTAMPDIFF(SECOND, t.trx_started, NOW())), 0) AS oldest_seconds,
+		  COALESCE(MAX(IF(p.command = 'Sleep', p.time, NULL)), 0) AS oldest_idle_seconds,
+		  COALESCE(SUM(t.trx_rows_locked), 0) AS rows_locked,
+		  COALESCE(SUM(t.trx_rows_modified), 0) AS rows_modified
+		  FROM information_schema.innodb_trx t
+		  LEFT JOIN %s p ON p.id = t.trx_mysql_thread_id
+		  GROUP BY t.trx_state
+		`
+
+const innodbTrxOldestQuery = `
+		SELECT
+		  COALESCE(p.user, '') AS user,
+		  COALESCE(SUBSTRING_INDEX(p.host, ':', 1), '') AS host,
+		  TIMESTAMPDIFF(SECOND, t.trx_started, NOW()) AS seconds
+		  FROM information_schema.innodb_trx t
+		  LEFT JOIN %s p ON p.id = t.trx_mysql_thread_id
+		  ORDER BY t.trx_started
+		  LIMIT %d
+		`
+
+// Metric descriptors.
+var (
+	infoSchemaInnodbTrxDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, informationSchema, "innodb_trx_transactions"),
+		"The number of open InnoDB transactions by state.",
+		[]string{"state"}, nil,
+	)
+	infoSchemaInnodbTrxIdleDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, informationSchema, "innodb_trx_idle_transactions"),
+		"The number of open InnoDB transactions whose session is idle (Sleep).",
+		nil, nil,
+	)
+	infoSchemaInnodbTrxOldestDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, informationSchema, "innodb_trx_oldest_seconds"),
+		"The age of the oldest open InnoDB transaction.",
+		nil, nil,
+	)
+	infoSchemaInnodbTrxOldestIdleDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, informationSchema, "innodb_trx_oldest_idle_seconds"),
+		"The longest time a session with an open InnoDB transaction has been idle.",
+		nil, nil,
+	)
+	infoSchemaInnodbTrxRowsLockedDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, informationSchema, "innodb_trx_rows_locked"),
+		"The approximate number of rows locked by open InnoDB transactions.",
+		nil, nil,
+	)
+	infoSchemaInnodbTrxRowsModifiedDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, informationSchema, "innodb_trx_rows_modified"),
+		"The number of rows modified and inserted by open InnoDB transactions.",
+		nil, nil,
+	)
+	infoSchemaInnodbTrxOldestTransactionDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, informationSchema, "innodb_trx_oldest_transaction_seconds"),
+		"The age of the oldest open InnoDB transactions, ranked from 1 for the oldest.",
+		[]string{"rank", "user", "host"}, nil,
+	)
+)
+
+// ScrapeInnodbTrx collects from `information_schema.innodb_trx`.
+type ScrapeInnodbTrx struct {
+	// Options of the scraper, DefaultInnodbTrxOptions() if nil.
+	Options *InnodbTrxOptions
+}
+
+// InnodbTrxOptions configures ScrapeInnodbTrx.
+type InnodbTrxOptions struct {
+	// TopN is the number of oldest transactions exposed with their user and
+	// host. Zero disables them.
+	TopN int
+}
+
+// DefaultInnodbTrxOptions returns the default options of ScrapeInnodbTrx.
+func DefaultInnodbTrxOptions() InnodbTrxOptions {
+	return InnodbTrxOptions{TopN: 10}
+}
+
+func (s ScrapeInnodbTrx) options() InnodbTrxOptions {
+	if s.Options == nil {
+		return DefaultInnodbTrxOptions()
+	}
+	return *s.Options
+}
+
+// Name of the Scraper. Should be unique.
+func (ScrapeInnodbTrx) Name() string {
+	return informationSchema + ".innodb_trx"
+}
+
+// Help describes the role of the Scraper.
+func (ScrapeInnodbTrx) Help() string {
+	return "Collect long-running and idle-in-transaction InnoDB transactions from information_schema.innodb_trx"
+}
+
+// Version of MySQL from which scraper is available.
+func (ScrapeInnodbTrx) Version() float64 {
+	return 5.6
+}
+
+// Privileges required by the Scraper.
+func (ScrapeInnodbTrx) Privileges() []Privilege {
+	return []Privilege{privProcess, privSelectPerfSchema}
+}
+
+// processlistTable returns the table listing the sessions of the instance.
+// performance_schema.processlist, available from MySQL 8.0.22, does not take
+// the global mutex that information_schema.processlist does.
+func processlistTable(instance *instance) string {
+	if instance.flavor == FlavorMySQL && instance.version.GTE(semver.MustParse("8.0.22")) {
+		return "performance_schema.processlist"
+	}
+	return "information_schema.processlist"
+}
+
+// Scrape collects data from database connection and sends it over channel as prometheus metric.
+func (s ScrapeInnodbTrx) Scrape(ctx context.Context, instance *instance, ch chan<- prometheus.Metric, logger *slog.Logger) error {
+	opts := s.options()
+	db := instance.getDB()
+	processlist := processlistTable(instance)
+	trxRows, err := db.QueryContext(ctx, fmt.Sprintf(innodbTrxQuery, processlist))
+	if err != nil {
+		return err
+	}
+	defer trxRows.Close()
+
+	var (
+		state                                         string
+		transactions, idle                            uint64
+		oldest, oldestIdle                            float64
+		rowsLocked, rowsModified                      uint64
+		totalIdle, totalRowsLocked, totalRowsModified uint64
+		maxOldest, maxOldestIdle                      float64
+	)
+	for trxRows.Next() {
+		if err := trxRows.Scan(&state, &transactions, &idle, &oldest, &oldestIdle, &rowsLocked, &rowsModified); err != nil {
+			return err
+		}
+		ch <- prometheus.MustNewConstMetric(infoSchemaInnodbTrxDesc, prometheus.GaugeValue, float64(transactions), sanitizeState(state))
+		totalIdle += idle
+		totalRowsLocked += rowsLocked
+		totalRowsModified += rowsModified
+		maxOldest = max(maxOldest, oldest)
+		maxOldestIdle = max(maxOldestIdle, oldestIdle)
+	}
+	if err := trxRows.Err(); err != nil {
+		return err
+	}
+	ch <- prometheus.MustNewConstMetric(infoSchemaInnodbTrxIdleDesc, prometheus.GaugeValue, float64(totalIdle))
+	ch <- prometheus.MustNewConstMetric(infoSchemaInnodbTrxOldestDesc, prometheus.GaugeValue, maxOldest)
+	ch <- prometheus.MustNewConstMetric(infoSchemaInnodbTrxOldestIdleDesc, prometheus.GaugeValue, maxOldestIdle)
+	ch <- prometheus.MustNewConstMetric(infoSchemaInnodbTrxRowsLockedDesc, prometheus.GaugeValue, float64(totalRowsLocked))
+	ch <- prometheus.MustNewConstMetric(infoSchemaInnodbTrxRowsModifiedDesc, prometheus.GaugeValue, float64(totalRowsModified))
+
+	if opts.TopN <= 0 {
+		return nil
+	}
+	oldestRows, err := db.QueryContext(ctx, fmt.Sprintf(innodbTrxOldestQuery, processlist, opts.TopN))
+	if err != nil {
+		return err
+	}
+	defer oldestRows.Close()
+
+	var (
+		user, host string
+		seconds    float64
+	)
+	for rank := 1; oldestRows.Next(); rank++ {
+		if err := oldestRows.Scan(&user, &host, &seconds); err != nil {
+			return err
+		}
+		ch <- prometheus.MustNewConstMetric(infoSchemaInnodbTrxOldestTransactionDesc, prometheus.GaugeValue, seconds, strconv.Itoa(rank), user, host)
+	}
+	return oldestRows.Err()
+}
+
+// check interface
+var _ PrivilegedScraper = ScrapeInnodbTrx{}
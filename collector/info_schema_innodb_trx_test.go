@@ -0,0 +1,112 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/blang/semver/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/promslog"
+	"github.com/smartystreets/goconvey/convey"
+)
+
+func TestScrapeInnodbTrx(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error opening a stub database connection: %s", err)
+	}
+	defer db.Close()
+	inst := &instance{db: db}
+
+	columns := []string{"trx_state", "transactions", "idle", "oldest_seconds", "oldest_idle_seconds", "rows_locked", "rows_modified"}
+	rows := sqlmock.NewRows(columns).
+		AddRow("RUNNING", 3, 2, 3600, 1200, 10, 250).
+		AddRow("LOCK WAIT", 1, 0, 30, 0, 1, 0)
+	mock.ExpectQuery(sanitizeQuery(fmt.Sprintf(innodbTrxQuery, "information_schema.processlist"))).WillReturnRows(rows)
+	oldestRows := sqlmock.NewRows([]string{"user", "host", "seconds"}).
+		AddRow("app", "10.0.0.1", 3600).
+		AddRow("app", "10.0.0.1", 1200)
+	mock.ExpectQuery(sanitizeQuery(fmt.Sprintf(innodbTrxOldestQuery, "information_schema.processlist", 2))).WillReturnRows(oldestRows)
+
+	ch := make(chan prometheus.Metric)
+	go func() {
+		if err = (ScrapeInnodbTrx{Options: &InnodbTrxOptions{TopN: 2}}).Scrape(context.Background(), inst, ch, promslog.NewNopLogger()); err != nil {
+			t.Errorf("error calling function on test: %s", err)
+		}
+		close(ch)
+	}()
+
+	expected := []MetricResult{
+		{labels: labelMap{"state": "running"}, value: 3, metricType: dto.MetricType_GAUGE},
+		{labels: labelMap{"state": "lock_wait"}, value: 1, metricType: dto.MetricType_GAUGE},
+		{labels: labelMap{}, value: 2, metricType: dto.MetricType_GAUGE},
+		{labels: labelMap{}, value: 3600, metricType: dto.MetricType_GAUGE},
+		{labels: labelMap{}, value: 1200, metricType: dto.MetricType_GAUGE},
+		{labels: labelMap{}, value: 11, metricType: dto.MetricType_GAUGE},
+		{labels: labelMap{}, value: 250, metricType: dto.MetricType_GAUGE},
+		{labels: labelMap{"rank": "1", "user": "app", "host": "10.0.0.1"}, value: 3600, metricType: dto.MetricType_GAUGE},
+		{labels: labelMap{"rank": "2", "user": "app", "host": "10.0.0.1"}, value: 1200, metricType: dto.MetricType_GAUGE},
+	}
+	convey.Convey("Metrics comparison", t, func() {
+		for _, expect := range expected {
+			got := readMetric(<-ch)
+			convey.So(got, convey.ShouldResemble, expect)
+		}
+	})
+
+	// Ensure all SQL queries were executed
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled exceptions: %s", err)
+	}
+}
+
+func TestScrapeInnodbTrxWithoutTopN(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error opening a stub database connection: %s", err)
+	}
+	defer db.Close()
+	inst := &instance{db: db, flavor: FlavorMySQL, version: semver.MustParse("8.0.36")}
+
+	// MySQL 8.0.22 and later read the sessions from performance_schema.
+	mock.ExpectQuery(sanitizeQuery(fmt.Sprintf(innodbTrxQuery, "performance_schema.processlist"))).WillReturnRows(sqlmock.NewRows([]string{"trx_state", "transactions", "idle", "oldest_seconds", "oldest_idle_seconds", "rows_locked", "rows_modified"}))
+
+	ch := make(chan prometheus.Metric)
+	go func() {
+		if err = (ScrapeInnodbTrx{Options: &InnodbTrxOptions{}}).Scrape(context.Background(), inst, ch, promslog.NewNopLogger()); err != nil {
+			t.Errorf("error calling function on test: %s", err)
+		}
+		close(ch)
+	}()
+
+	convey.Convey("Without transactions the aggregates are zero", t, func() {
+		var got []MetricResult
+		for m := range ch {
+			got = append(got, readMetric(m))
+		}
+		convey.So(got, convey.ShouldHaveLength, 5)
+		for _, m := range got {
+			convey.So(m.value, convey.ShouldEqual, 0)
+		}
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled exceptions: %s", err)
+	}
+}
@@ -23,7 +23,6 @@ import (
 	"slices"
 	"strings"
 
-	"github.com/alecthomas/kingpin/v2"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
@@ -41,22 +40,6 @@ const infoSchemaProcesslistQuery = `
 		  GROUP BY user, host, command, state
 	`
 
-// Tunable flags.
-var (
-	processlistMinTime = kingpin.Flag(
-		"collect.info_schema.processlist.min_time",
-		"Minimum time a thread must be in each state to be counted",
-	).Default("0").Int()
-	processesByUserFlag = kingpin.Flag(
-		"collect.info_schema.processlist.processes_by_user",
-		"Enable collecting the number of processes by user",
-	).Default("true").Bool()
-	processesByHostFlag = kingpin.Flag(
-		"collect.info_schema.processlist.processes_by_host",
-		"Enable collecting the number of processes by host",
-	).Default("true").Bool()
-)
-
 // Metric descriptors.
 var (
 	processlistCountDesc = prometheus.NewDesc(
@@ -78,7 +61,33 @@ var (
 )
 
 // ScrapeProcesslist collects from `information_schema.processlist`.
-type ScrapeProcesslist struct{}
+type ScrapeProcesslist struct {
+	// Options of the scraper, DefaultProcesslistOptions() if nil.
+	Options *ProcesslistOptions
+}
+
+// ProcesslistOptions configures ScrapeProcesslist.
+type ProcesslistOptions struct {
+	// MinTime is the minimum time, in seconds, a thread must be in each state
+	// to be counted.
+	MinTime int
+	// ProcessesByUser enables collecting the number of processes by user.
+	ProcessesByUser bool
+	// ProcessesByHost enables collecting the number of processes by host.
+	ProcessesByHost bool
+}
+
+// DefaultProcesslistOptions returns the default options of ScrapeProcesslist.
+func DefaultProcesslistOptions() ProcesslistOptions {
+	return ProcesslistOptions{ProcessesByUser: true, ProcessesByHost: true}
+}
+
+func (s ScrapeProcesslist) options() ProcesslistOptions {
+	if s.Options == nil {
+		return DefaultProcesslistOptions()
+	}
+	return *s.Options
+}
 
 // Name of the Scraper. Should be unique.
 func (ScrapeProcesslist) Name() string {
@@ -95,11 +104,17 @@ func (ScrapeProcesslist) Version() float64 {
 	return 5.1
 }
 
+// Privileges required by the Scraper.
+func (ScrapeProcesslist) Privileges() []Privilege {
+	return []Privilege{privProcess}
+}
+
 // Scrape collects data from database connection and sends it over channel as prometheus metric.
-func (ScrapeProcesslist) Scrape(ctx context.Context, instance *instance, ch chan<- prometheus.Metric, logger *slog.Logger) error {
+func (s ScrapeProcesslist) Scrape(ctx context.Context, instance *instance, ch chan<- prometheus.Metric, logger *slog.Logger) error {
+	opts := s.options()
 	processQuery := fmt.Sprintf(
 		infoSchemaProcesslistQuery,
-		*processlistMinTime,
+		opts.MinTime,
 	)
 	db := instance.getDB()
 	processlistRows, err := db.QueryContext(ctx, processQuery)
@@ -162,12 +177,12 @@ func (ScrapeProcesslist) Scrape(ctx context.Context, instance *instance, ch chan
 		}
 	}
 
-	if *processesByHostFlag {
+	if opts.ProcessesByHost {
 		for _, host := range slices.Sorted(maps.Keys(stateHostCounts)) {
 			ch <- prometheus.MustNewConstMetric(processesByHostDesc, prometheus.GaugeValue, float64(stateHostCounts[host]), host)
 		}
 	}
-	if *processesByUserFlag {
+	if opts.ProcessesByUser {
 		for _, user := range slices.Sorted(maps.Keys(stateUserCounts)) {
 			ch <- prometheus.MustNewConstMetric(processesByUserDesc, prometheus.GaugeValue, float64(stateUserCounts[user]), user)
 		}
@@ -198,4 +213,4 @@ func sanitizeState(state string) string {
 }
 
 // check interface
-var _ Scraper = ScrapeProcesslist{}
+var _ PrivilegedScraper = ScrapeProcesslist{}
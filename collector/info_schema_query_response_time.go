@@ -54,7 +54,7 @@ var (
 	}
 )
 
-func processQueryResponseTimeTable(ctx context.Context, instance *instance, ch chan<- prometheus.Metric, query string, i int) error {
+func processQueryResponseTimeTable(ctx context.Context, instance *instance, ch chan<- prometheus.Metric, query string, i int, nativeHistograms NativeHistogramOptions) error {
 	db := instance.getDB()
 	queryDistributionRows, err := db.QueryContext(ctx, query)
 	if err != nil {
@@ -69,6 +69,8 @@ func processQueryResponseTimeTable(ctx context.Context, instance *instance, ch c
 		histogramCnt uint64
 		histogramSum float64
 		countBuckets = map[float64]uint64{}
+		native       = newNativeBuckets(nativeHistograms.Schema)
+		tooLong      uint64
 	)
 
 	for queryDistributionRows.Next() {
@@ -86,11 +88,22 @@ func processQueryResponseTimeTable(ctx context.Context, instance *instance, ch c
 		histogramCnt += count
 		histogramSum += total
 		// Special case for "TOO LONG" row where we take into account the count field which is the only available
-		// and do not add it as a part of histogram or metric
+		// and do not add it as a part of histogram or metric. Its queries only count in the +Inf bucket.
 		if length == 0 {
+			tooLong += count
 			continue
 		}
 		countBuckets[length] = histogramCnt
+		native.add(length, count)
+	}
+	if nativeHistograms.Enabled {
+		native.addInf(tooLong)
+		m, err := native.metric(infoSchemaQueryResponseTimeCountDescs[i], histogramSum)
+		if err != nil {
+			return err
+		}
+		ch <- m
+		return nil
 	}
 	// Create histogram with query counts
 	ch <- prometheus.MustNewConstHistogram(
@@ -100,7 +113,30 @@ func processQueryResponseTimeTable(ctx context.Context, instance *instance, ch c
 }
 
 // ScrapeQueryResponseTime collects from `information_schema.query_response_time`.
-type ScrapeQueryResponseTime struct{}
+type ScrapeQueryResponseTime struct {
+	// Options of the scraper, DefaultQueryResponseTimeOptions() if nil.
+	Options *QueryResponseTimeOptions
+}
+
+// QueryResponseTimeOptions configures ScrapeQueryResponseTime.
+type QueryResponseTimeOptions struct {
+	// NativeHistograms exposes the distributions as native histograms
+	// instead of classic histograms.
+	NativeHistograms NativeHistogramOptions
+}
+
+// DefaultQueryResponseTimeOptions returns the default options of
+// ScrapeQueryResponseTime.
+func DefaultQueryResponseTimeOptions() QueryResponseTimeOptions {
+	return QueryResponseTimeOptions{NativeHistograms: DefaultNativeHistogramOptions()}
+}
+
+func (s ScrapeQueryResponseTime) options() QueryResponseTimeOptions {
+	if s.Options == nil {
+		return DefaultQueryResponseTimeOptions()
+	}
+	return *s.Options
+}
 
 // Name of the Scraper. Should be unique.
 func (ScrapeQueryResponseTime) Name() string {
@@ -117,8 +153,13 @@ func (ScrapeQueryResponseTime) Version() float64 {
 	return 5.5
 }
 
+// Privileges required by the Scraper.
+func (ScrapeQueryResponseTime) Privileges() []Privilege {
+	return []Privilege{privProcess}
+}
+
 // Scrape collects data from database connection and sends it over channel as prometheus metric.
-func (ScrapeQueryResponseTime) Scrape(ctx context.Context, instance *instance, ch chan<- prometheus.Metric, logger *slog.Logger) error {
+func (s ScrapeQueryResponseTime) Scrape(ctx context.Context, instance *instance, ch chan<- prometheus.Metric, logger *slog.Logger) error {
 	var queryStats uint8
 	db := instance.getDB()
 	err := db.QueryRowContext(ctx, queryResponseCheckQuery).Scan(&queryStats)
@@ -132,7 +173,7 @@ func (ScrapeQueryResponseTime) Scrape(ctx context.Context, instance *instance, c
 	}
 
 	for i, query := range queryResponseTimeQueries {
-		err := processQueryResponseTimeTable(ctx, instance, ch, query, i)
+		err := processQueryResponseTimeTable(ctx, instance, ch, query, i, s.options().NativeHistograms)
 		// The first query should not fail if query_response_time_stats is ON,
 		// unlike the other two when the read/write tables exist only with Percona Server 5.6/5.7.
 		if i == 0 && err != nil {
@@ -143,4 +184,4 @@ func (ScrapeQueryResponseTime) Scrape(ctx context.Context, instance *instance, c
 }
 
 // check interface
-var _ Scraper = ScrapeQueryResponseTime{}
+var _ PrivilegedScraper = ScrapeQueryResponseTime{}
@@ -92,3 +92,45 @@ func TestScrapeQueryResponseTime(t *testing.T) {
 		t.Errorf("there were unfulfilled exceptions: %s", err)
 	}
 }
+
+func TestScrapeQueryResponseTimeNative(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error opening a stub database connection: %s", err)
+	}
+	defer db.Close()
+	inst := &instance{db: db}
+
+	opts := QueryResponseTimeOptions{NativeHistograms: NativeHistogramOptions{Enabled: true, Schema: 0}}
+
+	mock.ExpectQuery(queryResponseCheckQuery).WillReturnRows(sqlmock.NewRows([]string{""}).AddRow(1))
+
+	rows := sqlmock.NewRows([]string{"TIME", "COUNT", "TOTAL"}).
+		AddRow(1.000000, 3, 1.5).
+		AddRow(2.000000, 0, 0.000000).
+		AddRow(4.000000, 2, 7).
+		AddRow("TOO LONG", 1, "TOO LONG")
+	mock.ExpectQuery(sanitizeQuery(queryResponseTimeQueries[0])).WillReturnRows(rows)
+
+	ch := make(chan prometheus.Metric)
+	go func() {
+		if err = (ScrapeQueryResponseTime{Options: &opts}).Scrape(context.Background(), inst, ch, promslog.NewNopLogger()); err != nil {
+			t.Errorf("error calling function on test: %s", err)
+		}
+		close(ch)
+	}()
+
+	gotPb := &dto.Metric{}
+	(<-ch).Write(gotPb)
+	convey.Convey("Native histogram", t, func() {
+		convey.So(gotPb.Histogram.GetSchema(), convey.ShouldEqual, 0)
+		convey.So(gotPb.Histogram.GetSampleCount(), convey.ShouldEqual, 6)
+		convey.So(gotPb.Histogram.GetSampleSum(), convey.ShouldEqual, 8.5)
+		convey.So(gotPb.Histogram.Bucket, convey.ShouldBeEmpty)
+		convey.So(gotPb.Histogram.PositiveDelta, convey.ShouldResemble, []int64{3, -3, 2, -1})
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled exceptions: %s", err)
+	}
+}
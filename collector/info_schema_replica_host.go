@@ -81,6 +81,11 @@ func (ScrapeReplicaHost) Version() float64 {
 	return 5.6
 }
 
+// Privileges required by the Scraper.
+func (ScrapeReplicaHost) Privileges() []Privilege {
+	return nil
+}
+
 // Scrape collects data from database connection and sends it over channel as prometheus metric.
 func (ScrapeReplicaHost) Scrape(ctx context.Context, instance *instance, ch chan<- prometheus.Metric, logger *slog.Logger) error {
 	db := instance.getDB()
@@ -143,4 +148,4 @@ func (ScrapeReplicaHost) Scrape(ctx context.Context, instance *instance, ch chan
 }
 
 // check interface
-var _ Scraper = ScrapeReplicaHost{}
+var _ PrivilegedScraper = ScrapeReplicaHost{}
@@ -242,6 +242,11 @@ func (ScrapeRocksDBPerfContext) Version() float64 {
 	return 5.6
 }
 
+// Privileges required by the Scraper.
+func (ScrapeRocksDBPerfContext) Privileges() []Privilege {
+	return []Privilege{privProcess}
+}
+
 // Scrape collects data from database connection and sends it over channel as prometheus metric.
 func (ScrapeRocksDBPerfContext) Scrape(ctx context.Context, instance *instance, ch chan<- prometheus.Metric, logger *slog.Logger) error {
 	db := instance.getDB()
@@ -270,4 +275,4 @@ func (ScrapeRocksDBPerfContext) Scrape(ctx context.Context, instance *instance,
 }
 
 // check interface
-var _ Scraper = ScrapeRocksDBPerfContext{}
+var _ PrivilegedScraper = ScrapeRocksDBPerfContext{}
@@ -18,9 +18,7 @@ package collector
 import (
 	"context"
 	"log/slog"
-	"strings"
 
-	"github.com/alecthomas/kingpin/v2"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
@@ -49,14 +47,6 @@ const (
 		`
 )
 
-// Tunable flags.
-var (
-	tableSchemaDatabases = kingpin.Flag(
-		"collect.info_schema.tables.databases",
-		"The list of databases to collect table stats for, or '*' for all",
-	).Default("*").String()
-)
-
 // Metric descriptors.
 var (
 	infoSchemaTablesVersionDesc = prometheus.NewDesc(
@@ -77,7 +67,16 @@ var (
 )
 
 // ScrapeTableSchema collects from `information_schema.tables`.
-type ScrapeTableSchema struct{}
+type ScrapeTableSchema struct {
+	// Options of the scraper, all databases if nil.
+	Options *TableSchemaOptions
+}
+
+// TableSchemaOptions configures ScrapeTableSchema.
+type TableSchemaOptions struct {
+	// Databases to collect table stats for. Empty means all databases.
+	Databases []string
+}
 
 // Name of the Scraper. Should be unique.
 func (ScrapeTableSchema) Name() string {
@@ -94,11 +93,21 @@ func (ScrapeTableSchema) Version() float64 {
 	return 5.1
 }
 
+// Privileges required by the Scraper.
+func (ScrapeTableSchema) Privileges() []Privilege {
+	return []Privilege{privSelectAll}
+}
+
+// Cost of the Scraper.
+func (ScrapeTableSchema) Cost() ScraperCost {
+	return CostExpensive
+}
+
 // Scrape collects data from database connection and sends it over channel as prometheus metric.
-func (ScrapeTableSchema) Scrape(ctx context.Context, instance *instance, ch chan<- prometheus.Metric, logger *slog.Logger) error {
+func (s ScrapeTableSchema) Scrape(ctx context.Context, instance *instance, ch chan<- prometheus.Metric, logger *slog.Logger) error {
 	var dbList []string
 	db := instance.getDB()
-	if *tableSchemaDatabases == "*" {
+	if s.Options == nil || len(s.Options.Databases) == 0 {
 		dbListRows, err := db.QueryContext(ctx, dbListQuery)
 		if err != nil {
 			return err
@@ -116,7 +125,7 @@ func (ScrapeTableSchema) Scrape(ctx context.Context, instance *instance, ch chan
 			dbList = append(dbList, database)
 		}
 	} else {
-		dbList = strings.Split(*tableSchemaDatabases, ",")
+		dbList = s.Options.Databases
 	}
 
 	for _, database := range dbList {
@@ -184,4 +193,4 @@ func (ScrapeTableSchema) Scrape(ctx context.Context, instance *instance, ch chan
 }
 
 // check interface
-var _ Scraper = ScrapeTableSchema{}
+var _ PrivilegedScraper = ScrapeTableSchema{}
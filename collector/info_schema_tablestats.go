@@ -69,6 +69,16 @@ func (ScrapeTableStat) Version() float64 {
 	return 5.1
 }
 
+// Privileges required by the Scraper.
+func (ScrapeTableStat) Privileges() []Privilege {
+	return []Privilege{privSelectAll}
+}
+
+// Cost of the Scraper.
+func (ScrapeTableStat) Cost() ScraperCost {
+	return CostExpensive
+}
+
 // Scrape collects data from database connection and sends it over channel as prometheus metric.
 func (ScrapeTableStat) Scrape(ctx context.Context, instance *instance, ch chan<- prometheus.Metric, logger *slog.Logger) error {
 	var varName, varVal string
@@ -125,4 +135,4 @@ func (ScrapeTableStat) Scrape(ctx context.Context, instance *instance, ch chan<-
 }
 
 // check interface
-var _ Scraper = ScrapeTableStat{}
+var _ PrivilegedScraper = ScrapeTableStat{}
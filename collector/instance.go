@@ -14,7 +14,9 @@
 package collector
 
 import (
+	"context"
 	"database/sql"
+	"database/sql/driver"
 	"fmt"
 	"regexp"
 	"strconv"
@@ -33,19 +35,18 @@ type instance struct {
 	flavor            string
 	version           semver.Version
 	versionMajorMinor float64
+	// percona is set for Percona Server, which has the MySQL flavor.
+	percona bool
 }
 
-func newInstance(dsn string) (*instance, error) {
+func newInstance(ctx context.Context, connector driver.Connector) (*instance, error) {
 	i := &instance{}
-	db, err := sql.Open("mysql", dsn)
-	if err != nil {
-		return nil, err
-	}
+	db := sql.OpenDB(connector)
 	db.SetMaxOpenConns(1)
 	db.SetMaxIdleConns(1)
 	i.db = db
 
-	version, versionString, err := queryVersion(db)
+	version, versionString, err := queryVersion(ctx, db)
 	if err != nil {
 		db.Close()
 		return nil, err
@@ -65,6 +66,7 @@ func newInstance(dsn string) (*instance, error) {
 		i.flavor = FlavorMariaDB
 	} else {
 		i.flavor = FlavorMySQL
+		i.percona = perconaVersionRegex.MatchString(versionString)
 	}
 
 	return i, nil
@@ -79,8 +81,8 @@ func (i *instance) Close() error {
 }
 
 // Ping checks connection availability and possibly invalidates the connection if it fails.
-func (i *instance) Ping() error {
-	if err := i.db.Ping(); err != nil {
+func (i *instance) Ping(ctx context.Context) error {
+	if err := i.db.PingContext(ctx); err != nil {
 		if cerr := i.Close(); cerr != nil {
 			return err
 		}
@@ -94,9 +96,13 @@ func (i *instance) Ping() error {
 // for MySQL: "8.0.36-28.1"
 var versionRegex = regexp.MustCompile(`^((\d+)(\.\d+)(\.\d+))`)
 
-func queryVersion(db *sql.DB) (semver.Version, string, error) {
+// Percona Server appends its release number to the MySQL version, e.g.
+// "8.0.36-28" or "5.7.44-48-log".
+var perconaVersionRegex = regexp.MustCompile(`^\d+\.\d+\.\d+-\d+(\.\d+)?\b`)
+
+func queryVersion(ctx context.Context, db *sql.DB) (semver.Version, string, error) {
 	var version string
-	err := db.QueryRow("SELECT @@version;").Scan(&version)
+	err := db.QueryRowContext(ctx, "SELECT @@version;").Scan(&version)
 	if err != nil {
 		return semver.Version{}, version, err
 	}
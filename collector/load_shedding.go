@@ -0,0 +1,119 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Skipping of expensive scrapers while the server is under load.
+
+package collector
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ScraperCost classifies scrapers by the load their queries put on the server.
+type ScraperCost int
+
+const (
+	// CostCheap scrapers read counters or small tables.
+	CostCheap ScraperCost = iota
+	// CostExpensive scrapers scan information_schema tables or
+	// performance_schema summaries whose size grows with the number of
+	// schemas, tables or statements.
+	CostExpensive
+)
+
+func (c ScraperCost) String() string {
+	if c == CostExpensive {
+		return "expensive"
+	}
+	return "cheap"
+}
+
+// CostedScraper is a Scraper that declares its cost.
+type CostedScraper interface {
+	Scraper
+
+	// Cost of the Scraper.
+	Cost() ScraperCost
+}
+
+// CostOf returns the cost of a scraper. Scrapers not declaring their cost are
+// cheap.
+func CostOf(scraper Scraper) ScraperCost {
+	if s, ok := scraper.(CostedScraper); ok {
+		return s.Cost()
+	}
+	return CostCheap
+}
+
+var (
+	collectorShedDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, exporter, "collector_shed"),
+		"Whether an expensive collector was skipped because the server was under load.",
+		[]string{"collector"}, nil,
+	)
+	loadSheddingSignalDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, exporter, "load_shedding_signal"),
+		"Value of the status variable deciding whether expensive collectors are skipped.",
+		[]string{"variable"}, nil,
+	)
+)
+
+var statusVariableRegex = regexp.MustCompile(`^\w+$`)
+
+// LoadShedding skips expensive scrapers while a global status variable,
+// e.g. Threads_running, exceeds a threshold.
+type LoadShedding struct {
+	// Variable is the name of the global status variable.
+	Variable string
+	// Threshold above which expensive scrapers are skipped. Zero disables
+	// load shedding.
+	Threshold float64
+}
+
+// SetLoadShedding skips expensive scrapers while the server is under load.
+func SetLoadShedding(l LoadShedding) ExporterOpt {
+	return func(e *Exporter) {
+		e.loadShedding = l
+	}
+}
+
+// enabled reports whether load shedding is configured.
+func (l LoadShedding) enabled() bool {
+	return l.Variable != "" && l.Threshold > 0
+}
+
+// overloaded reads the status variable and reports whether it exceeds the
+// threshold.
+func (l LoadShedding) overloaded(ctx context.Context, instance *instance, ch chan<- prometheus.Metric) (bool, float64, error) {
+	if !statusVariableRegex.MatchString(l.Variable) {
+		return false, 0, fmt.Errorf("invalid status variable %q", l.Variable)
+	}
+	var name, value string
+	// An exact match, as _ is a wildcard in LIKE. SHOW statements cannot be
+	// prepared on MySQL 5.7, so the validated name is inlined.
+	query := fmt.Sprintf("SHOW GLOBAL STATUS WHERE Variable_name = '%s'", l.Variable)
+	if err := instance.db.QueryRowContext(ctx, query).Scan(&name, &value); err != nil {
+		return false, 0, err
+	}
+	v, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return false, 0, fmt.Errorf("status variable %s is not numeric: %w", name, err)
+	}
+	ch <- prometheus.MustNewConstMetric(loadSheddingSignalDesc, prometheus.GaugeValue, v, name)
+	return v > l.Threshold, v, nil
+}
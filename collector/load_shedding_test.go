@@ -0,0 +1,90 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/promslog"
+	"github.com/smartystreets/goconvey/convey"
+)
+
+type expensiveScraper struct{}
+
+func (expensiveScraper) Name() string      { return "expensive" }
+func (expensiveScraper) Help() string      { return "" }
+func (expensiveScraper) Version() float64  { return 5.1 }
+func (expensiveScraper) Cost() ScraperCost { return CostExpensive }
+
+func (expensiveScraper) Scrape(context.Context, *instance, chan<- prometheus.Metric, *slog.Logger) error {
+	return nil
+}
+
+func TestLoadShedding(t *testing.T) {
+	value := func(s string) *string { return &s }
+	for _, threadsRunning := range []string{"12", "120"} {
+		snapshot := &Snapshot{Queries: []SnapshotQuery{
+			{Query: "SELECT @@version;", Columns: []SnapshotColumn{{Name: "@@version"}}, Rows: [][]*string{{value("8.0.36")}}},
+			{
+				Query:   "SHOW GLOBAL STATUS WHERE Variable_name = 'Threads_running'",
+				Columns: []SnapshotColumn{{Name: "Variable_name"}, {Name: "Value"}},
+				Rows:    [][]*string{{value("Threads_running"), value(threadsRunning)}},
+			},
+			{
+				Query:   globalStatusQuery,
+				Columns: []SnapshotColumn{{Name: "Variable_name"}, {Name: "Value"}},
+				Rows:    [][]*string{{value("Threads_running"), value(threadsRunning)}},
+			},
+		}}
+		var result ScrapeResult
+		exporter := New(context.Background(), dsn, []Scraper{ScrapeGlobalStatus{}, expensiveScraper{}}, promslog.NewNopLogger(),
+			ReplaySnapshot(snapshot),
+			SetLoadShedding(LoadShedding{Variable: "Threads_running", Threshold: 64}),
+			SetResultHandler(func(r ScrapeResult) { result = r }))
+
+		convey.Convey("Expensive collectors are skipped under load (Threads_running: "+threadsRunning+")", t, func() {
+			ch := make(chan prometheus.Metric)
+			go func() {
+				exporter.Collect(ch)
+				close(ch)
+			}()
+			shed := map[string]float64{}
+			var signal float64
+			for m := range ch {
+				switch m.Desc() {
+				case collectorShedDesc:
+					got := readMetric(m)
+					shed[got.labels["collector"]] = got.value
+				case loadSheddingSignalDesc:
+					signal = readMetric(m).value
+				}
+			}
+			overloaded := 0.0
+			if threadsRunning == "120" {
+				overloaded = 1
+			}
+			convey.So(shed, convey.ShouldResemble, map[string]float64{
+				"collect.global_status": 0,
+				"collect.expensive":     overloaded,
+			})
+			convey.So(signal, convey.ShouldNotEqual, 0)
+			for _, c := range result.Collectors {
+				convey.So(c.Err == nil, convey.ShouldEqual, !c.Shed)
+			}
+		})
+	}
+}
@@ -0,0 +1,267 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Filtering and label rewriting of the metrics sent by the exporter.
+
+package collector
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"google.golang.org/protobuf/proto"
+)
+
+// MetricRules filter and rewrite the metrics sent by Exporter.Collect.
+type MetricRules struct {
+	// Keep drops metrics whose name does not match, if set.
+	Keep *regexp.Regexp
+	// Drop drops metrics whose name matches, if set.
+	Drop *regexp.Regexp
+	// DropLabels lists the names of labels to remove. Series that become
+	// identical are dropped, except for the first one.
+	DropLabels []string
+	// RenameLabels maps label names to new names.
+	RenameLabels map[string]string
+	// HashLength replaces label values longer than this many bytes by a hash
+	// of the value. Zero disables hashing.
+	HashLength int
+	// Privacy hashes or redacts sensitive label values, by their original
+	// label name.
+	Privacy Privacy
+}
+
+// SetMetricRules filters and rewrites the metrics sent by the exporter.
+func SetMetricRules(rules MetricRules) ExporterOpt {
+	return func(e *Exporter) {
+		e.metricRules = rules
+	}
+}
+
+func (r MetricRules) empty() bool {
+	return r.Keep == nil && r.Drop == nil && len(r.DropLabels) == 0 && len(r.RenameLabels) == 0 && r.HashLength <= 0 && !r.Privacy.enabled()
+}
+
+// hashLabelValue returns a stable, shortened hash of a label value.
+func hashLabelValue(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:8])
+}
+
+// merges reports whether the rules can make series identical, in which case
+// their values are merged.
+func (r MetricRules) merges() bool {
+	return len(r.DropLabels) > 0 || r.Privacy.redacts()
+}
+
+// apply forwards the metrics from in to out, filtered and rewritten by the
+// rules, until in is closed. Series made identical by dropped or redacted
+// labels are merged, and sent once in is closed.
+func (r MetricRules) apply(in <-chan prometheus.Metric, out chan<- prometheus.Metric, logger *slog.Logger) {
+	merged := map[string]*mergedSeries{}
+	var order []string
+	for m := range in {
+		m, key, err := r.rewrite(m)
+		if err != nil {
+			logger.Error("Error applying metric rules", "err", err)
+			continue
+		}
+		if m == nil {
+			continue
+		}
+		if !r.merges() || key == "" {
+			out <- m
+			continue
+		}
+		s, ok := merged[key]
+		if !ok {
+			merged[key] = &mergedSeries{first: m}
+			order = append(order, key)
+			continue
+		}
+		if err := s.add(m); err != nil {
+			logger.Error("Error merging series made identical by metric rules", "err", err)
+		}
+	}
+	for _, key := range order {
+		m, err := merged[key].metric()
+		if err != nil {
+			logger.Error("Error merging series made identical by metric rules", "err", err)
+			continue
+		}
+		out <- m
+	}
+}
+
+// rewrite returns the metric rewritten by the rules, or nil if it is dropped,
+// and the key identifying its series if the rules merge series. Metrics whose
+// descriptor cannot be parsed are returned unchanged, without a key.
+func (r MetricRules) rewrite(m prometheus.Metric) (prometheus.Metric, string, error) {
+	name, help, err := descNameAndHelp(m.Desc())
+	if err != nil {
+		return m, "", nil
+	}
+	if r.Keep != nil && !r.Keep.MatchString(name) {
+		return nil, "", nil
+	}
+	if r.Drop != nil && r.Drop.MatchString(name) {
+		return nil, "", nil
+	}
+	if len(r.DropLabels) == 0 && len(r.RenameLabels) == 0 && r.HashLength <= 0 && !r.Privacy.enabled() {
+		return m, "", nil
+	}
+
+	var pb dto.Metric
+	if err := m.Write(&pb); err != nil {
+		return nil, "", err
+	}
+	changed := false
+	labels := make([]*dto.LabelPair, 0, len(pb.Label))
+	for _, l := range pb.Label {
+		labelName, value := l.GetName(), l.GetValue()
+		if slices.Contains(r.DropLabels, labelName) {
+			changed = true
+			continue
+		}
+		protected, isProtected := r.Privacy.protect(labelName, value)
+		if isProtected {
+			value = protected
+			changed = true
+		}
+		if newName, ok := r.RenameLabels[labelName]; ok {
+			labelName = newName
+			changed = true
+		}
+		if !isProtected && r.HashLength > 0 && len(value) > r.HashLength {
+			value = hashLabelValue(value)
+			changed = true
+		}
+		labels = append(labels, &dto.LabelPair{Name: &labelName, Value: &value})
+	}
+	slices.SortFunc(labels, func(a, b *dto.LabelPair) int {
+		return cmp.Compare(a.GetName(), b.GetName())
+	})
+
+	labelNames := make([]string, 0, len(labels))
+	var key strings.Builder
+	key.WriteString(name)
+	for i, l := range labels {
+		if i > 0 && l.GetName() == labels[i-1].GetName() {
+			return nil, "", fmt.Errorf("metric %s has duplicate label %q after renaming labels", name, l.GetName())
+		}
+		labelNames = append(labelNames, l.GetName())
+		key.WriteString("\xff" + l.GetName() + "\xff" + l.GetValue())
+	}
+	if !changed {
+		return m, key.String(), nil
+	}
+
+	return rewrittenMetric{
+		Metric: m,
+		desc:   prometheus.NewDesc(name, help, labelNames, nil),
+		labels: labels,
+	}, key.String(), nil
+}
+
+// mergedSeries sums the values of series made identical by the rules.
+// Counters, gauges and classic histograms are summed.
+type mergedSeries struct {
+	first prometheus.Metric
+	sum   *dto.Metric
+}
+
+func (s *mergedSeries) add(m prometheus.Metric) error {
+	if s.sum == nil {
+		var first dto.Metric
+		if err := s.first.Write(&first); err != nil {
+			return err
+		}
+		// Const metrics share their values with the written dto.
+		s.sum = proto.Clone(&first).(*dto.Metric)
+	}
+	var pb dto.Metric
+	if err := m.Write(&pb); err != nil {
+		return err
+	}
+	sum := s.sum
+	switch {
+	case sum.Counter != nil && pb.Counter != nil:
+		sum.Counter.Value = addFloat(sum.Counter.GetValue(), pb.Counter.GetValue())
+	case sum.Gauge != nil && pb.Gauge != nil:
+		sum.Gauge.Value = addFloat(sum.Gauge.GetValue(), pb.Gauge.GetValue())
+	case sum.Untyped != nil && pb.Untyped != nil:
+		sum.Untyped.Value = addFloat(sum.Untyped.GetValue(), pb.Untyped.GetValue())
+	case sum.Histogram != nil && pb.Histogram != nil && sum.Histogram.Schema == nil && pb.Histogram.Schema == nil:
+		if len(sum.Histogram.Bucket) != len(pb.Histogram.Bucket) {
+			return fmt.Errorf("cannot merge histograms %s with different buckets", s.first.Desc())
+		}
+		sum.Histogram.SampleSum = addFloat(sum.Histogram.GetSampleSum(), pb.Histogram.GetSampleSum())
+		count := sum.Histogram.GetSampleCount() + pb.Histogram.GetSampleCount()
+		sum.Histogram.SampleCount = &count
+		for i, b := range pb.Histogram.Bucket {
+			if b.GetUpperBound() != sum.Histogram.Bucket[i].GetUpperBound() {
+				return fmt.Errorf("cannot merge histograms %s with different buckets", s.first.Desc())
+			}
+			cumulative := sum.Histogram.Bucket[i].GetCumulativeCount() + b.GetCumulativeCount()
+			sum.Histogram.Bucket[i].CumulativeCount = &cumulative
+		}
+	default:
+		return fmt.Errorf("cannot merge series of %s of this type, dropping labels or redacting label values made them identical", s.first.Desc())
+	}
+	return nil
+}
+
+func addFloat(a, b float64) *float64 {
+	sum := a + b
+	return &sum
+}
+
+// metric returns the merged series.
+func (s *mergedSeries) metric() (prometheus.Metric, error) {
+	if s.sum == nil {
+		return s.first, nil
+	}
+	return rewrittenMetric{Metric: s.first, desc: s.first.Desc(), labels: s.sum.Label, merged: s.sum}, nil
+}
+
+// rewrittenMetric is a metric with a different descriptor and labels, and
+// merged values if set.
+type rewrittenMetric struct {
+	prometheus.Metric
+	desc   *prometheus.Desc
+	labels []*dto.LabelPair
+	merged *dto.Metric
+}
+
+func (m rewrittenMetric) Desc() *prometheus.Desc {
+	return m.desc
+}
+
+func (m rewrittenMetric) Write(pb *dto.Metric) error {
+	if err := m.Metric.Write(pb); err != nil {
+		return err
+	}
+	pb.Label = m.labels
+	if m.merged != nil {
+		pb.Counter, pb.Gauge, pb.Untyped, pb.Histogram = m.merged.Counter, m.merged.Gauge, m.merged.Untyped, m.merged.Histogram
+	}
+	return nil
+}
@@ -0,0 +1,192 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"errors"
+	"maps"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/promslog"
+	"github.com/smartystreets/goconvey/convey"
+)
+
+func TestMetricRules(t *testing.T) {
+	longText := "SELECT " + strings.Repeat("a, ", 20) + "b FROM t"
+	metrics := []prometheus.Metric{
+		prometheus.MustNewConstMetric(performanceSchemaEventsStatementsDesc, prometheus.CounterValue, 1, "db1", "digest1", longText),
+		prometheus.MustNewConstMetric(performanceSchemaEventsStatementsDesc, prometheus.CounterValue, 2, "db1", "digest2", "SELECT 1"),
+		prometheus.MustNewConstMetric(performanceSchemaEventsStatementsErrorsDesc, prometheus.CounterValue, 3, "db1", "digest1", longText),
+		prometheus.MustNewConstMetric(mysqlUp, prometheus.GaugeValue, 1),
+	}
+
+	run := func(rules MetricRules) []MetricResult {
+		in := make(chan prometheus.Metric)
+		out := make(chan prometheus.Metric)
+		go func() {
+			for _, m := range metrics {
+				in <- m
+			}
+			close(in)
+		}()
+		go func() {
+			rules.apply(in, out, promslog.NewNopLogger())
+			close(out)
+		}()
+		var got []MetricResult
+		for m := range out {
+			got = append(got, readMetric(m))
+		}
+		return got
+	}
+
+	convey.Convey("Metrics are dropped by name", t, func() {
+		got := run(MetricRules{
+			Keep: regexp.MustCompile(`^mysql_(up|perf_schema_.*)$`),
+			Drop: regexp.MustCompile(`^.*_errors_total$`),
+		})
+		convey.So(got, convey.ShouldHaveLength, 3)
+		convey.So(got[2], convey.ShouldResemble, MetricResult{labels: labelMap{}, value: 1, metricType: dto.MetricType_GAUGE})
+	})
+
+	convey.Convey("Labels are dropped, renamed and hashed", t, func() {
+		got := run(MetricRules{
+			Drop:         regexp.MustCompile(`^mysql_up$`),
+			DropLabels:   []string{"digest_text"},
+			RenameLabels: map[string]string{"schema": "database"},
+			HashLength:   6,
+		})
+		convey.So(got, convey.ShouldResemble, []MetricResult{
+			{labels: labelMap{"database": "db1", "digest": hashLabelValue("digest1")}, value: 1, metricType: dto.MetricType_COUNTER},
+			{labels: labelMap{"database": "db1", "digest": hashLabelValue("digest2")}, value: 2, metricType: dto.MetricType_COUNTER},
+			{labels: labelMap{"database": "db1", "digest": hashLabelValue("digest1")}, value: 3, metricType: dto.MetricType_COUNTER},
+		})
+	})
+
+	convey.Convey("Series made identical by dropping labels are summed", t, func() {
+		got := run(MetricRules{DropLabels: []string{"digest", "digest_text"}})
+		convey.So(got, convey.ShouldResemble, []MetricResult{
+			{labels: labelMap{"schema": "db1"}, value: 3, metricType: dto.MetricType_COUNTER},
+			{labels: labelMap{"schema": "db1"}, value: 3, metricType: dto.MetricType_COUNTER},
+			{labels: labelMap{}, value: 1, metricType: dto.MetricType_GAUGE},
+		})
+	})
+
+	convey.Convey("Metrics with duplicate labels after renaming are dropped", t, func() {
+		got := run(MetricRules{RenameLabels: map[string]string{"digest": "schema"}})
+		convey.So(got, convey.ShouldResemble, []MetricResult{
+			{labels: labelMap{}, value: 1, metricType: dto.MetricType_GAUGE},
+		})
+	})
+
+	convey.Convey("Sensitive labels are hashed or redacted by class", t, func() {
+		got := run(MetricRules{
+			Drop:         regexp.MustCompile(`^mysql_up$`),
+			RenameLabels: map[string]string{"schema": "database"},
+			Privacy: Privacy{
+				Modes: map[LabelClass]PrivacyMode{LabelClassSchema: PrivacyHash, LabelClassDigestText: PrivacyRedact},
+				Salt:  "salt",
+			},
+		})
+		convey.So(got, convey.ShouldResemble, []MetricResult{
+			{labels: labelMap{"database": hashLabelValue("saltdb1"), "digest": "digest1", "digest_text": "redacted"}, value: 1, metricType: dto.MetricType_COUNTER},
+			{labels: labelMap{"database": hashLabelValue("saltdb1"), "digest": "digest2", "digest_text": "redacted"}, value: 2, metricType: dto.MetricType_COUNTER},
+			{labels: labelMap{"database": hashLabelValue("saltdb1"), "digest": "digest1", "digest_text": "redacted"}, value: 3, metricType: dto.MetricType_COUNTER},
+		})
+	})
+
+	convey.Convey("Series made identical by redaction are summed", t, func() {
+		got := run(MetricRules{
+			DropLabels: []string{"digest"},
+			Privacy:    Privacy{Modes: map[LabelClass]PrivacyMode{LabelClassDigestText: PrivacyRedact}},
+		})
+		convey.So(got, convey.ShouldHaveLength, 3)
+		convey.So(got[0].value, convey.ShouldEqual, 3)
+	})
+
+	convey.Convey("Metrics with an unknown descriptor are passed through", t, func() {
+		err := errors.New("invalid")
+		m := prometheus.NewInvalidMetric(prometheus.NewInvalidDesc(err), err)
+		for _, rules := range []MetricRules{
+			{Keep: regexp.MustCompile(`^mysql_up$`)},
+			{DropLabels: []string{"digest"}},
+			{Privacy: Privacy{Modes: map[LabelClass]PrivacyMode{LabelClassDigestText: PrivacyRedact}}},
+		} {
+			got, key, err := rules.rewrite(m)
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(got, convey.ShouldEqual, m)
+			convey.So(key, convey.ShouldBeEmpty)
+		}
+	})
+
+	convey.Convey("Label values are hashed stably", t, func() {
+		convey.So(hashLabelValue(longText), convey.ShouldEqual, hashLabelValue(longText))
+		convey.So(hashLabelValue(longText), convey.ShouldHaveLength, 16)
+		convey.So(hashLabelValue("a"), convey.ShouldNotEqual, hashLabelValue("b"))
+	})
+}
+
+func TestPrivacyLabelsByCollector(t *testing.T) {
+	rules := MetricRules{Privacy: Privacy{Modes: map[LabelClass]PrivacyMode{
+		LabelClassHost:   PrivacyRedact,
+		LabelClassSchema: PrivacyRedact,
+		LabelClassTable:  PrivacyRedact,
+	}}}
+	tests := []struct {
+		name   string
+		metric prometheus.Metric
+		want   labelMap
+	}{
+		{
+			name:   "table io waits",
+			metric: prometheus.MustNewConstMetric(performanceSchemaTableWaitsDesc, prometheus.CounterValue, 1, "tenant_db", "orders", "fetch"),
+			want:   labelMap{"schema": "redacted", "name": "redacted", "operation": "fetch"},
+		},
+		{
+			name:   "index io waits",
+			metric: prometheus.MustNewConstMetric(performanceSchemaIndexWaitsDesc, prometheus.CounterValue, 1, "tenant_db", "orders", "PRIMARY", "fetch"),
+			want:   labelMap{"schema": "redacted", "name": "redacted", "index": "PRIMARY", "operation": "fetch"},
+		},
+		{
+			name:   "table lock waits",
+			metric: prometheus.MustNewConstMetric(performanceSchemaSQLTableLockWaitsDesc, prometheus.CounterValue, 1, "tenant_db", "orders", "read_normal"),
+			want:   labelMap{"schema": "redacted", "name": "redacted", "operation": "read_normal"},
+		},
+		{
+			name:   "file instances",
+			metric: prometheus.MustNewConstMetric(performanceSchemaFileInstancesBytesDesc, prometheus.CounterValue, 1, "tenant_db/orders.ibd", "innodb_data_file", "read"),
+			want:   labelMap{"file_name": "redacted", "event_name": "innodb_data_file", "mode": "read"},
+		},
+		{
+			name:   "slave hosts",
+			metric: prometheus.MustNewConstMetric(SlaveHostsInfo, prometheus.GaugeValue, 1, "2", "replica1.example.com", "3306", "1", "uuid"),
+			want:   labelMap{"server_id": "2", "slave_host": "redacted", "port": "3306", "master_id": "1", "slave_uuid": "uuid"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, _, err := rules.rewrite(tt.metric)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := readMetric(m).labels; !maps.Equal(got, tt.want) {
+				t.Errorf("got labels %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -22,7 +22,6 @@ import (
 	"log/slog"
 	"strings"
 
-	"github.com/alecthomas/kingpin/v2"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
@@ -69,14 +68,6 @@ const mysqlUserQuery = `
 		  FROM mysql.user
 		`
 
-// Tunable flags.
-var (
-	userPrivilegesFlag = kingpin.Flag(
-		"collect.mysql.user.privileges",
-		"Enable collecting user privileges from mysql.user",
-	).Default("false").Bool()
-)
-
 var (
 	labelNames = []string{"mysql_user", "hostmask"}
 )
@@ -102,7 +93,16 @@ var (
 )
 
 // ScrapeUser collects from `information_schema.processlist`.
-type ScrapeUser struct{}
+type ScrapeUser struct {
+	// Options of the scraper, no privileges are collected if nil.
+	Options *UserOptions
+}
+
+// UserOptions configures ScrapeUser.
+type UserOptions struct {
+	// Privileges enables collecting user privileges from mysql.user.
+	Privileges bool
+}
 
 // Name of the Scraper. Should be unique.
 func (ScrapeUser) Name() string {
@@ -119,8 +119,13 @@ func (ScrapeUser) Version() float64 {
 	return 5.1
 }
 
+// Privileges required by the Scraper.
+func (ScrapeUser) Privileges() []Privilege {
+	return []Privilege{{Name: "SELECT", Object: "mysql.user"}}
+}
+
 // Scrape collects data from database connection and sends it over channel as prometheus metric.
-func (ScrapeUser) Scrape(ctx context.Context, instance *instance, ch chan<- prometheus.Metric, logger *slog.Logger) error {
+func (s ScrapeUser) Scrape(ctx context.Context, instance *instance, ch chan<- prometheus.Metric, logger *slog.Logger) error {
 	db := instance.getDB()
 	var (
 		userRows *sql.Rows
@@ -214,7 +219,7 @@ func (ScrapeUser) Scrape(ctx context.Context, instance *instance, ch chan<- prom
 			return err
 		}
 
-		if *userPrivilegesFlag {
+		if s.Options != nil && s.Options.Privileges {
 			userCols, err := userRows.Columns()
 			if err != nil {
 				return err
@@ -0,0 +1,225 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Metric naming schemes.
+
+package collector
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// MetricsNaming selects how metrics are named.
+type MetricsNaming string
+
+const (
+	// NamingV1 keeps the historical metric names and units.
+	NamingV1 MetricsNaming = "v1"
+	// NamingV2 converts values to base units and applies OpenMetrics
+	// suffixes, i.e. unit suffixes and `_total` on counters.
+	NamingV2 MetricsNaming = "v2"
+)
+
+// SetMetricsNaming selects the naming scheme of the metrics sent by the scrapers.
+func SetMetricsNaming(naming MetricsNaming) ExporterOpt {
+	return func(e *Exporter) {
+		e.naming = naming
+	}
+}
+
+// metricRename renames a metric and scales its value.
+type metricRename struct {
+	name  string
+	help  string
+	scale float64
+}
+
+// v2Renames lists metrics whose name lacks a unit or whose value is not in a
+// base unit. Counters get their `_total` suffix and gauges lose it separately.
+var v2Renames = map[string]metricRename{
+	"mysql_info_schema_replica_host_cpu_percent": {
+		name:  "mysql_info_schema_replica_host_cpu_ratio",
+		help:  "The CPU usage as a ratio between 0 and 1.",
+		scale: 0.01,
+	},
+	"mysql_info_schema_rocksdb_perf_context_block_read_byte": {
+		name: "mysql_info_schema_rocksdb_perf_context_block_read_bytes",
+	},
+	"mysql_info_schema_replica_host_log_stream_speed": {
+		name:  "mysql_info_schema_replica_host_log_stream_speed_bytes_per_second",
+		help:  "The log stream speed in bytes per second.",
+		scale: 1024,
+	},
+	"mysql_info_schema_table_size": {
+		name: "mysql_info_schema_table_size_bytes",
+	},
+	"mysql_perf_schema_events_statements_latency": {
+		name: "mysql_perf_schema_events_statements_latency_seconds",
+	},
+	"mysql_perf_schema_events_statements_sum_lock_time": {
+		name:  "mysql_perf_schema_events_statements_sum_lock_time_seconds",
+		help:  "Time in seconds spent waiting for locks.",
+		scale: 1 / picoSeconds,
+	},
+	"mysql_perf_schema_events_statements_sum_timer_wait": {
+		name: "mysql_perf_schema_events_statements_sum_timer_wait_seconds",
+	},
+	"mysql_sys_statement_latency": {
+		name: "mysql_sys_statement_latency_seconds",
+	},
+}
+
+// catalogUnits maps the units of the variables catalog to base units.
+var catalogUnits = map[string]struct {
+	unit  string
+	scale float64
+}{
+	"bytes":        {"bytes", 1},
+	"seconds":      {"seconds", 1},
+	"milliseconds": {"seconds", 1e-3},
+	"microseconds": {"seconds", 1e-6},
+	"nanoseconds":  {"seconds", 1e-9},
+}
+
+// catalogRename derives the v2 name of a SHOW GLOBAL STATUS or SHOW GLOBAL
+// VARIABLES metric from the unit recorded in the variables catalog.
+func catalogRename(fqName, help string) (metricRename, bool) {
+	var info variableInfo
+	if key, ok := strings.CutPrefix(fqName, namespace+"_"+globalStatus+"_"); ok {
+		info = globalStatusCatalog[key]
+	} else if key, ok := strings.CutPrefix(fqName, namespace+"_"+globalVariables+"_"); ok {
+		info = globalVariablesCatalog[key]
+	}
+	base, ok := catalogUnits[info.Unit]
+	if !ok {
+		return metricRename{}, false
+	}
+	r := metricRename{name: fqName, scale: base.scale}
+	if !strings.HasSuffix(fqName, "_"+base.unit) && !strings.Contains(fqName, "_"+base.unit+"_") {
+		r.name += "_" + base.unit
+	}
+	if suffix := " Unit: " + info.Unit + "."; strings.HasSuffix(help, suffix) {
+		r.help = strings.TrimSuffix(help, suffix) + " Unit: " + base.unit + "."
+	}
+	return r, true
+}
+
+var descRE = regexp.MustCompile(`^Desc{fqName: ("(?:[^"\\]|\\.)*"), help: ("(?:[^"\\]|\\.)*")`)
+
+// descNameAndHelp extracts the name and help string of a Desc from its
+// String(), as prometheus.Desc does not expose them.
+func descNameAndHelp(desc *prometheus.Desc) (string, string, error) {
+	match := descRE.FindStringSubmatch(desc.String())
+	if match == nil || match[1] == `""` {
+		return "", "", fmt.Errorf("unexpected descriptor %s", desc)
+	}
+	name, err := strconv.Unquote(match[1])
+	if err != nil {
+		return "", "", err
+	}
+	help, err := strconv.Unquote(match[2])
+	if err != nil {
+		return "", "", err
+	}
+	return name, help, nil
+}
+
+// renameV2 returns the metric named according to NamingV2. Metrics whose
+// descriptor cannot be parsed are returned unchanged.
+func renameV2(m prometheus.Metric) (prometheus.Metric, error) {
+	name, help, err := descNameAndHelp(m.Desc())
+	if err != nil {
+		return m, nil
+	}
+	var pb dto.Metric
+	if err := m.Write(&pb); err != nil {
+		return nil, err
+	}
+
+	r, ok := v2Renames[name]
+	if !ok {
+		r, ok = catalogRename(name, help)
+	}
+	if !ok {
+		r = metricRename{name: name}
+	}
+	if r.help == "" {
+		r.help = help
+	}
+	if r.scale == 0 {
+		r.scale = 1
+	}
+	if pb.Counter != nil && !strings.HasSuffix(r.name, "_total") {
+		r.name += "_total"
+	}
+	if pb.Gauge != nil {
+		r.name = strings.TrimSuffix(r.name, "_total")
+	}
+	if r.name == name && r.help == help && r.scale == 1 {
+		return m, nil
+	}
+
+	labelNames := make([]string, 0, len(pb.Label))
+	labelValues := make([]string, 0, len(pb.Label))
+	for _, l := range pb.Label {
+		labelNames = append(labelNames, l.GetName())
+		labelValues = append(labelValues, l.GetValue())
+	}
+	desc := prometheus.NewDesc(r.name, r.help, labelNames, nil)
+
+	// Native histograms keep their buckets, which cannot be scaled.
+	if pb.Histogram != nil && pb.Histogram.Schema != nil {
+		if r.scale != 1 {
+			return nil, fmt.Errorf("cannot scale native histogram %s", name)
+		}
+		return rewrittenMetric{Metric: m, desc: desc, labels: pb.Label}, nil
+	}
+
+	var renamed prometheus.Metric
+	switch {
+	case pb.Counter != nil:
+		renamed, err = prometheus.NewConstMetric(desc, prometheus.CounterValue, pb.Counter.GetValue()*r.scale, labelValues...)
+	case pb.Gauge != nil:
+		renamed, err = prometheus.NewConstMetric(desc, prometheus.GaugeValue, pb.Gauge.GetValue()*r.scale, labelValues...)
+	case pb.Untyped != nil:
+		renamed, err = prometheus.NewConstMetric(desc, prometheus.UntypedValue, pb.Untyped.GetValue()*r.scale, labelValues...)
+	case pb.Summary != nil:
+		quantiles := make(map[float64]float64, len(pb.Summary.Quantile))
+		for _, q := range pb.Summary.Quantile {
+			quantiles[q.GetQuantile()] = q.GetValue() * r.scale
+		}
+		renamed, err = prometheus.NewConstSummary(desc, pb.Summary.GetSampleCount(), pb.Summary.GetSampleSum()*r.scale, quantiles, labelValues...)
+	case pb.Histogram != nil:
+		buckets := make(map[float64]uint64, len(pb.Histogram.Bucket))
+		for _, b := range pb.Histogram.Bucket {
+			buckets[b.GetUpperBound()*r.scale] = b.GetCumulativeCount()
+		}
+		renamed, err = prometheus.NewConstHistogram(desc, pb.Histogram.GetSampleCount(), pb.Histogram.GetSampleSum()*r.scale, buckets, labelValues...)
+	default:
+		return m, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if pb.TimestampMs != nil {
+		renamed = prometheus.NewMetricWithTimestamp(time.UnixMilli(pb.GetTimestampMs()), renamed)
+	}
+	return renamed, nil
+}
@@ -0,0 +1,117 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/smartystreets/goconvey/convey"
+)
+
+func TestRenameV2(t *testing.T) {
+	tests := []struct {
+		metric prometheus.Metric
+		name   string
+		help   string
+		value  float64
+	}{
+		{
+			metric: prometheus.MustNewConstMetric(performanceSchemaEventsStatementsSumLockTimeDesc, prometheus.CounterValue, 2e12),
+			name:   "mysql_perf_schema_events_statements_sum_lock_time_seconds_total",
+			help:   "Time in seconds spent waiting for locks.",
+			value:  2,
+		},
+		{
+			metric: prometheus.MustNewConstMetric(newDesc(globalStatus, "innodb_row_lock_time", "The total time spent acquiring row locks. Unit: milliseconds."), prometheus.CounterValue, 1500),
+			name:   "mysql_global_status_innodb_row_lock_time_seconds_total",
+			help:   "The total time spent acquiring row locks. Unit: seconds.",
+			value:  1.5,
+		},
+		{
+			metric: prometheus.MustNewConstMetric(newDesc(globalVariables, "innodb_buffer_pool_size", "Buffer pool size."), prometheus.GaugeValue, 1024),
+			name:   "mysql_global_variables_innodb_buffer_pool_size_bytes",
+			help:   "Buffer pool size.",
+			value:  1024,
+		},
+		{
+			metric: prometheus.MustNewConstMetric(newDesc(globalStatus, "bytes_received", "Received."), prometheus.CounterValue, 10),
+			name:   "mysql_global_status_bytes_received_total",
+			help:   "Received.",
+			value:  10,
+		},
+		{
+			metric: prometheus.MustNewConstMetric(infoSchemaReplicaHostLogStreamSpeedDesc, prometheus.GaugeValue, 2, "1", "reader"),
+			name:   "mysql_info_schema_replica_host_log_stream_speed_bytes_per_second",
+			help:   "The log stream speed in bytes per second.",
+			value:  2048,
+		},
+		{
+			metric: prometheus.MustNewConstMetric(infoSchemaReplicaHostCpuDesc, prometheus.GaugeValue, 25, "1", "reader"),
+			name:   "mysql_info_schema_replica_host_cpu_ratio",
+			help:   "The CPU usage as a ratio between 0 and 1.",
+			value:  0.25,
+		},
+		{
+			metric: prometheus.MustNewConstMetric(newDesc(informationSchema, "innodb_cmpmem_pages_used_total", "Used."), prometheus.GaugeValue, 4),
+			name:   "mysql_info_schema_innodb_cmpmem_pages_used",
+			help:   "Used.",
+			value:  4,
+		},
+		{
+			metric: prometheus.MustNewConstMetric(newDesc(globalStatus, "threads_running", "Running."), prometheus.GaugeValue, 3),
+			name:   "mysql_global_status_threads_running",
+			help:   "Running.",
+			value:  3,
+		},
+	}
+
+	convey.Convey("Metrics are renamed", t, func() {
+		for _, test := range tests {
+			got, err := renameV2(test.metric)
+			convey.So(err, convey.ShouldBeNil)
+			name, help, err := descNameAndHelp(got.Desc())
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(name, convey.ShouldEqual, test.name)
+			convey.So(help, convey.ShouldEqual, test.help)
+			pb := readMetric(got)
+			convey.So(pb.value, convey.ShouldEqual, test.value)
+		}
+	})
+
+	convey.Convey("Labels and summaries are preserved", t, func() {
+		m := prometheus.MustNewConstSummary(performanceSchemaEventsStatementsLatency, 3, 1.5, map[float64]float64{95: 0.5}, "db", "abc", "SELECT ?")
+		got, err := renameV2(m)
+		convey.So(err, convey.ShouldBeNil)
+		name, _, err := descNameAndHelp(got.Desc())
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(name, convey.ShouldEqual, "mysql_perf_schema_events_statements_latency_seconds")
+
+		var pb dto.Metric
+		convey.So(got.Write(&pb), convey.ShouldBeNil)
+		convey.So(pb.Summary.GetSampleCount(), convey.ShouldEqual, 3)
+		convey.So(pb.Summary.GetSampleSum(), convey.ShouldEqual, 1.5)
+		convey.So(len(pb.Label), convey.ShouldEqual, 3)
+	})
+
+	convey.Convey("Metrics with an unknown descriptor are passed through", t, func() {
+		err := errors.New("invalid")
+		m := prometheus.NewInvalidMetric(prometheus.NewInvalidDesc(err), err)
+		got, err := renameV2(m)
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(got, convey.ShouldEqual, m)
+	})
+}
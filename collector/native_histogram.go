@@ -0,0 +1,121 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Conversion of server side latency buckets to native histograms.
+
+package collector
+
+import (
+	"math"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// NativeHistogramOptions configures scrapers exposing latency distributions.
+type NativeHistogramOptions struct {
+	// Enabled exposes latency distributions as native histograms. Requires
+	// scraping with the protobuf format.
+	Enabled bool
+	// Schema is the resolution of the native histograms, from -4 to 8.
+	// Bucket boundaries grow by a factor of 2^(2^-schema).
+	Schema int32
+}
+
+// DefaultNativeHistogramOptions returns the default native histogram options.
+func DefaultNativeHistogramOptions() NativeHistogramOptions {
+	return NativeHistogramOptions{Schema: 3}
+}
+
+// nativeBuckets accumulates the counts of classic buckets into the buckets of
+// a native histogram.
+type nativeBuckets struct {
+	schema int32
+	// Counts of positive buckets by index. Bucket i covers
+	// (2^((i-1)/2^schema), 2^(i/2^schema)].
+	positive map[int]int64
+	// Count of observations of zero or less.
+	zero uint64
+}
+
+func newNativeBuckets(schema int32) *nativeBuckets {
+	return &nativeBuckets{schema: schema, positive: map[int]int64{}}
+}
+
+// index returns the index of the native bucket containing v.
+func (b *nativeBuckets) index(v float64) int {
+	// The small offset keeps exact bucket boundaries in their own bucket
+	// despite rounding errors.
+	return int(math.Ceil(math.Log2(v)*math.Exp2(float64(b.schema)) - 1e-9))
+}
+
+// add records count observations in the classic bucket with the given upper
+// bound. The observations are assigned to the native bucket containing the
+// upper bound, so no observation ends up in a lower bucket than it belongs.
+func (b *nativeBuckets) add(upperBound float64, count uint64) {
+	if count == 0 {
+		return
+	}
+	if upperBound <= 0 {
+		b.zero += count
+		return
+	}
+	b.positive[b.index(upperBound)] += int64(count)
+}
+
+// addInf records count observations above all bounds, like the +Inf bucket
+// of a classic histogram. As in client_golang, they are assigned to the
+// bucket following the one containing math.MaxFloat64.
+func (b *nativeBuckets) addInf(count uint64) {
+	if count == 0 {
+		return
+	}
+	b.positive[b.index(math.MaxFloat64)+1] += int64(count)
+}
+
+// count returns the number of observations in all buckets.
+func (b *nativeBuckets) count() uint64 {
+	total := b.zero
+	for _, c := range b.positive {
+		total += uint64(c)
+	}
+	return total
+}
+
+// metric returns a native histogram of the buckets.
+func (b *nativeBuckets) metric(desc *prometheus.Desc, sum float64, labelValues ...string) (prometheus.Metric, error) {
+	m, err := prometheus.NewConstNativeHistogram(
+		desc, b.count(), sum, b.positive, nil, b.zero, b.schema, 0, time.Time{}, labelValues...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return withoutCreatedTimestamp{m}, nil
+}
+
+// withoutCreatedTimestamp drops the created timestamp of a histogram. The
+// server does not report when its counters were reset.
+type withoutCreatedTimestamp struct {
+	prometheus.Metric
+}
+
+func (m withoutCreatedTimestamp) Write(pb *dto.Metric) error {
+	if err := m.Metric.Write(pb); err != nil {
+		return err
+	}
+	if pb.Histogram != nil {
+		pb.Histogram.CreatedTimestamp = nil
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"math"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/smartystreets/goconvey/convey"
+)
+
+func TestNativeBuckets(t *testing.T) {
+	convey.Convey("Bucket indexes", t, func() {
+		b := newNativeBuckets(0)
+		convey.So(b.index(1), convey.ShouldEqual, 0)
+		convey.So(b.index(2), convey.ShouldEqual, 1)
+		convey.So(b.index(3), convey.ShouldEqual, 2)
+		convey.So(b.index(0.5), convey.ShouldEqual, -1)
+
+		b = newNativeBuckets(3)
+		convey.So(b.index(2), convey.ShouldEqual, 8)
+		convey.So(b.index(0.001), convey.ShouldEqual, -79)
+	})
+
+	convey.Convey("+Inf observations use the bucket of client_golang", t, func() {
+		for _, factor := range []float64{16, 2, 1.1, 1.003} {
+			h := prometheus.NewHistogram(prometheus.HistogramOpts{Name: "h", NativeHistogramBucketFactor: factor})
+			h.Observe(math.Inf(1))
+			var pb dto.Metric
+			convey.So(h.Write(&pb), convey.ShouldBeNil)
+			b := newNativeBuckets(pb.Histogram.GetSchema())
+			b.addInf(1)
+			convey.So(b.positive, convey.ShouldResemble, map[int]int64{int(pb.Histogram.PositiveSpan[0].GetOffset()): 1})
+		}
+	})
+
+	convey.Convey("Classic buckets are converted", t, func() {
+		b := newNativeBuckets(0)
+		b.add(0, 1)
+		b.add(1, 2)
+		b.add(1.5, 3)
+		b.add(2, 4)
+		b.add(4, 0)
+		b.addInf(5)
+		convey.So(b.zero, convey.ShouldEqual, 1)
+		convey.So(b.positive, convey.ShouldResemble, map[int]int64{0: 2, 1: 7, 1025: 5})
+		convey.So(b.count(), convey.ShouldEqual, 15)
+
+		m, err := b.metric(performanceSchemaEventsStatementsLatency, 12.5, "db1", "digest1", "SELECT 1")
+		convey.So(err, convey.ShouldBeNil)
+		var pb dto.Metric
+		convey.So(m.Write(&pb), convey.ShouldBeNil)
+		convey.So(pb.Histogram.GetSchema(), convey.ShouldEqual, 0)
+		convey.So(pb.Histogram.GetSampleCount(), convey.ShouldEqual, 15)
+		convey.So(pb.Histogram.GetSampleSum(), convey.ShouldEqual, 12.5)
+		convey.So(pb.Histogram.GetZeroCount(), convey.ShouldEqual, 1)
+		convey.So(pb.Histogram.PositiveDelta, convey.ShouldResemble, []int64{2, 5, -2})
+		convey.So(pb.Histogram.CreatedTimestamp, convey.ShouldBeNil)
+		convey.So(pb.Histogram.Bucket, convey.ShouldBeEmpty)
+	})
+
+	convey.Convey("Empty buckets", t, func() {
+		b := newNativeBuckets(3)
+		b.addInf(2)
+		convey.So(b.positive, convey.ShouldResemble, map[int]int64{8193: 2})
+	})
+}
@@ -17,12 +17,12 @@ package collector
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"log/slog"
 	"slices"
 	"strings"
 
-	"github.com/alecthomas/kingpin/v2"
 	"github.com/blang/semver/v4"
 	"github.com/prometheus/client_golang/prometheus"
 )
@@ -48,7 +48,7 @@ const perfEventsStatementsQuery = `
 	    SELECT *
 	    FROM performance_schema.events_statements_summary_by_digest
 	    WHERE SCHEMA_NAME NOT IN (%s)
-	      AND LAST_SEEN > DATE_SUB(NOW(), INTERVAL %d SECOND)
+	      AND LAST_SEEN > DATE_SUB(NOW(), INTERVAL %d SECOND)%s
 	    ORDER BY LAST_SEEN DESC
 	  )Q
 	  GROUP BY
@@ -97,7 +97,7 @@ const perfEventsStatementsQueryMySQL = `
 	    SELECT *
 	    FROM performance_schema.events_statements_summary_by_digest
 	    WHERE SCHEMA_NAME NOT IN (%s)
-	      AND LAST_SEEN > DATE_SUB(NOW(), INTERVAL %d SECOND)
+	      AND LAST_SEEN > DATE_SUB(NOW(), INTERVAL %d SECOND)%s
 	    ORDER BY LAST_SEEN DESC
 	  )Q
 	  GROUP BY
@@ -125,25 +125,17 @@ const perfEventsStatementsQueryMySQL = `
 	  LIMIT %d
 	`
 
-// Tunable flags.
-var (
-	perfEventsStatementsLimit = kingpin.Flag(
-		"collect.perf_schema.eventsstatements.limit",
-		"Limit the number of events statements digests by response time",
-	).Default("250").Int()
-	perfEventsStatementsTimeLimit = kingpin.Flag(
-		"collect.perf_schema.eventsstatements.timelimit",
-		"Limit how old the 'last_seen' events statements can be, in seconds",
-	).Default("86400").Int()
-	perfEventsStatementsDigestTextLimit = kingpin.Flag(
-		"collect.perf_schema.eventsstatements.digest_text_limit",
-		"Maximum length of the normalized statement text",
-	).Default("120").Int()
-	perfEventsStatementsExcludeSchemas = kingpin.Flag(
-		"collect.perf_schema.eventsstatements.exclude_schemas",
-		"Additional schema name to exclude (always excludes mysql, performance_schema, information_schema). Repeatable",
-	).Default("").Strings()
-)
+const perfEventsStatementsHistogramQuery = `
+	SELECT
+	    ifnull(SCHEMA_NAME, 'NONE') as SCHEMA_NAME,
+	    DIGEST,
+	    BUCKET_TIMER_HIGH,
+	    COUNT_BUCKET
+	  FROM performance_schema.events_statements_histogram_by_digest
+	  WHERE DIGEST IS NOT NULL
+	    AND DIGEST IN (%s)
+	    AND COUNT_BUCKET > 0
+	`
 
 var defaultExcludedSchemas = []string{"'mysql'", "'performance_schema'", "'information_schema'"}
 
@@ -227,7 +219,49 @@ var (
 )
 
 // ScrapePerfEventsStatements collects from `performance_schema.events_statements_summary_by_digest`.
-type ScrapePerfEventsStatements struct{}
+type ScrapePerfEventsStatements struct {
+	// Options of the scraper, DefaultPerfEventsStatementsOptions() if nil.
+	Options *PerfEventsStatementsOptions
+}
+
+// PerfEventsStatementsOptions configures ScrapePerfEventsStatements.
+type PerfEventsStatementsOptions struct {
+	// Limit the number of events statements digests by response time.
+	Limit int
+	// TimeLimit limits how old the 'last_seen' events statements can be, in
+	// seconds.
+	TimeLimit int
+	// DigestTextLimit is the maximum length of the normalized statement text.
+	DigestTextLimit int
+	// ExcludeSchemas are additional schemas to exclude. mysql,
+	// performance_schema and information_schema are always excluded.
+	ExcludeSchemas []string
+	// ExcludeExporterStatements excludes the exporter's own statements, by
+	// the comment of their sample query text. Requires MySQL 8.0.3 or later
+	// and EnableQueryComments.
+	ExcludeExporterStatements bool
+	// NativeHistograms exposes the latency distributions of MySQL 8.0.28 and
+	// later as native histograms instead of summaries.
+	NativeHistograms NativeHistogramOptions
+}
+
+// DefaultPerfEventsStatementsOptions returns the default options of
+// ScrapePerfEventsStatements.
+func DefaultPerfEventsStatementsOptions() PerfEventsStatementsOptions {
+	return PerfEventsStatementsOptions{
+		Limit:            250,
+		TimeLimit:        86400,
+		DigestTextLimit:  120,
+		NativeHistograms: DefaultNativeHistogramOptions(),
+	}
+}
+
+func (s ScrapePerfEventsStatements) options() PerfEventsStatementsOptions {
+	if s.Options == nil {
+		return DefaultPerfEventsStatementsOptions()
+	}
+	return *s.Options
+}
 
 // Name of the Scraper. Should be unique.
 func (ScrapePerfEventsStatements) Name() string {
@@ -244,8 +278,19 @@ func (ScrapePerfEventsStatements) Version() float64 {
 	return 5.6
 }
 
+// Privileges required by the Scraper.
+func (ScrapePerfEventsStatements) Privileges() []Privilege {
+	return []Privilege{privSelectPerfSchema}
+}
+
+// Cost of the Scraper.
+func (ScrapePerfEventsStatements) Cost() ScraperCost {
+	return CostExpensive
+}
+
 // Scrape collects data from database connection and sends it over channel as prometheus metric.
-func (ScrapePerfEventsStatements) Scrape(ctx context.Context, instance *instance, ch chan<- prometheus.Metric, logger *slog.Logger) error {
+func (s ScrapePerfEventsStatements) Scrape(ctx context.Context, instance *instance, ch chan<- prometheus.Metric, logger *slog.Logger) error {
+	opts := s.options()
 	mysqlVersion8028 := instance.flavor == FlavorMySQL && instance.version.GTE(semver.MustParse("8.0.28"))
 
 	perfQuery := perfEventsStatementsQuery
@@ -253,14 +298,20 @@ func (ScrapePerfEventsStatements) Scrape(ctx context.Context, instance *instance
 		perfQuery = perfEventsStatementsQueryMySQL
 	}
 
-	excludeSchemasList := buildExcludedSchemasList(*perfEventsStatementsExcludeSchemas)
+	excludeSchemasList := buildExcludedSchemasList(opts.ExcludeSchemas)
+
+	var filter string
+	if f := exporterStatementsFilter(instance, opts.ExcludeExporterStatements); f != "" {
+		filter = "\n\t      AND " + f
+	}
 
 	perfQuery = fmt.Sprintf(
 		perfQuery,
-		*perfEventsStatementsDigestTextLimit,
+		opts.DigestTextLimit,
 		excludeSchemasList,
-		*perfEventsStatementsTimeLimit,
-		*perfEventsStatementsLimit,
+		opts.TimeLimit,
+		filter,
+		opts.Limit,
 	)
 
 	db := instance.getDB()
@@ -280,6 +331,9 @@ func (ScrapePerfEventsStatements) Scrape(ctx context.Context, instance *instance
 		sortMergePasses, sortRows            uint64
 		noIndexUsed                          uint64
 		quantile95, quantile99, quantile999  uint64
+
+		// Latency sums by digest, if exposed as native histograms.
+		nativeLatencies []digestLatency
 	)
 	for perfSchemaEventsStatementsRows.Next() {
 		var err error
@@ -351,12 +405,76 @@ func (ScrapePerfEventsStatements) Scrape(ctx context.Context, instance *instance
 			performanceSchemaEventsStatementsNoIndexUsedDesc, prometheus.CounterValue, float64(noIndexUsed),
 			schemaName, digest, digestText,
 		)
+		if opts.NativeHistograms.Enabled && mysqlVersion8028 {
+			nativeLatencies = append(nativeLatencies, digestLatency{
+				schema: schemaName, digest: digest, digestText: digestText, sum: float64(queryTime) / picoSeconds,
+			})
+			continue
+		}
 		ch <- prometheus.MustNewConstSummary(performanceSchemaEventsStatementsLatency, count, float64(queryTime)/picoSeconds, map[float64]float64{
 			95:  float64(quantile95) / picoSeconds,
 			99:  float64(quantile99) / picoSeconds,
 			999: float64(quantile999) / picoSeconds,
 		}, schemaName, digest, digestText)
 	}
+	if err := perfSchemaEventsStatementsRows.Err(); err != nil {
+		return err
+	}
+	if len(nativeLatencies) > 0 {
+		return scrapeEventsStatementsHistograms(ctx, db, nativeLatencies, opts.NativeHistograms.Schema, ch)
+	}
+	return nil
+}
+
+// digestLatency identifies a statement digest and its total latency.
+type digestLatency struct {
+	schema, digest, digestText string
+	sum                        float64
+}
+
+// scrapeEventsStatementsHistograms sends the latency distribution of the
+// digests as native histograms. Only the buckets of the digests selected by the
+// summary query are read.
+func scrapeEventsStatementsHistograms(ctx context.Context, db *sql.DB, latencies []digestLatency, schema int32, ch chan<- prometheus.Metric) error {
+	buckets := make(map[[2]string]*nativeBuckets, len(latencies))
+	var digests []any
+	for _, l := range latencies {
+		buckets[[2]string{l.schema, l.digest}] = newNativeBuckets(schema)
+		if !slices.Contains(digests, any(l.digest)) {
+			digests = append(digests, l.digest)
+		}
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(digests)), ", ")
+
+	rows, err := db.QueryContext(ctx, fmt.Sprintf(perfEventsStatementsHistogramQuery, placeholders), digests...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var (
+		schemaName, digest string
+		timerHigh, count   uint64
+	)
+	for rows.Next() {
+		if err := rows.Scan(&schemaName, &digest, &timerHigh, &count); err != nil {
+			return err
+		}
+		if b, ok := buckets[[2]string{schemaName, digest}]; ok {
+			b.add(float64(timerHigh)/picoSeconds, count)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, l := range latencies {
+		m, err := buckets[[2]string{l.schema, l.digest}].metric(performanceSchemaEventsStatementsLatency, l.sum, l.schema, l.digest, l.digestText)
+		if err != nil {
+			return err
+		}
+		ch <- m
+	}
 	return nil
 }
 
@@ -373,4 +491,4 @@ func buildExcludedSchemasList(extraSchemas []string) string {
 }
 
 // check interface
-var _ Scraper = ScrapePerfEventsStatements{}
+var _ PrivilegedScraper = ScrapePerfEventsStatements{}
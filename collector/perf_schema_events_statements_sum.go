@@ -17,6 +17,7 @@ package collector
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -45,7 +46,7 @@ const perfEventsStatementsSumQuery = `
 		SUM(SUM_SORT_SCAN) AS SUM_SUM_SORT_SCAN,
 		SUM(SUM_TIMER_WAIT) AS SUM_SUM_TIMER_WAIT,
 		SUM(SUM_WARNINGS) AS SUM_SUM_WARNINGS
-	FROM performance_schema.events_statements_summary_by_digest;
+	FROM performance_schema.events_statements_summary_by_digest%s;
 	`
 
 // Metric descriptors.
@@ -158,7 +159,18 @@ var (
 )
 
 // ScrapePerfEventsStatementsSum collects from `performance_schema.events_statements_summary_by_digest`.
-type ScrapePerfEventsStatementsSum struct{}
+type ScrapePerfEventsStatementsSum struct {
+	// Options of the scraper, defaults if nil.
+	Options *PerfEventsStatementsSumOptions
+}
+
+// PerfEventsStatementsSumOptions configures ScrapePerfEventsStatementsSum.
+type PerfEventsStatementsSumOptions struct {
+	// ExcludeExporterStatements excludes the exporter's own statements, by
+	// the comment of their sample query text. Requires MySQL 8.0.3 or later
+	// and EnableQueryComments.
+	ExcludeExporterStatements bool
+}
 
 // Name of the Scraper. Should be unique.
 func (ScrapePerfEventsStatementsSum) Name() string {
@@ -175,11 +187,25 @@ func (ScrapePerfEventsStatementsSum) Version() float64 {
 	return 5.7
 }
 
+// Privileges required by the Scraper.
+func (ScrapePerfEventsStatementsSum) Privileges() []Privilege {
+	return []Privilege{privSelectPerfSchema}
+}
+
+// Cost of the Scraper.
+func (ScrapePerfEventsStatementsSum) Cost() ScraperCost {
+	return CostExpensive
+}
+
 // Scrape collects data from database connection and sends it over channel as prometheus metric.
-func (ScrapePerfEventsStatementsSum) Scrape(ctx context.Context, instance *instance, ch chan<- prometheus.Metric, logger *slog.Logger) error {
+func (s ScrapePerfEventsStatementsSum) Scrape(ctx context.Context, instance *instance, ch chan<- prometheus.Metric, logger *slog.Logger) error {
 	db := instance.getDB()
 	// Timers here are returned in picoseconds.
-	perfEventsStatementsSumRows, err := db.QueryContext(ctx, perfEventsStatementsSumQuery)
+	var filter string
+	if f := exporterStatementsFilter(instance, s.Options != nil && s.Options.ExcludeExporterStatements); f != "" {
+		filter = "\n\tWHERE " + f
+	}
+	perfEventsStatementsSumRows, err := db.QueryContext(ctx, fmt.Sprintf(perfEventsStatementsSumQuery, filter))
 	if err != nil {
 		return err
 	}
@@ -273,4 +299,4 @@ func (ScrapePerfEventsStatementsSum) Scrape(ctx context.Context, instance *insta
 }
 
 // check interface
-var _ Scraper = ScrapePerfEventsStatementsSum{}
+var _ PrivilegedScraper = ScrapePerfEventsStatementsSum{}
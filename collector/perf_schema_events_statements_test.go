@@ -49,7 +49,8 @@ func TestScrapePerfEventsStatements(t *testing.T) {
 			1, 2, 3,
 			100, 1)
 
-	query := fmt.Sprintf(perfEventsStatementsQuery, *perfEventsStatementsDigestTextLimit, buildExcludedSchemasList(*perfEventsStatementsExcludeSchemas), *perfEventsStatementsTimeLimit, *perfEventsStatementsLimit)
+	opts := DefaultPerfEventsStatementsOptions()
+	query := fmt.Sprintf(perfEventsStatementsQuery, opts.DigestTextLimit, buildExcludedSchemasList(opts.ExcludeSchemas), opts.TimeLimit, "", opts.Limit)
 	mock.ExpectQuery(sanitizeQuery(query)).WillReturnRows(rows)
 
 	ch := make(chan prometheus.Metric)
@@ -125,7 +126,8 @@ func TestScrapePerfEventsStatementsMySQL8028(t *testing.T) {
 			100, 1,
 			100, 150, 200)
 
-	query := fmt.Sprintf(perfEventsStatementsQueryMySQL, *perfEventsStatementsDigestTextLimit, buildExcludedSchemasList(*perfEventsStatementsExcludeSchemas), *perfEventsStatementsTimeLimit, *perfEventsStatementsLimit)
+	opts := DefaultPerfEventsStatementsOptions()
+	query := fmt.Sprintf(perfEventsStatementsQueryMySQL, opts.DigestTextLimit, buildExcludedSchemasList(opts.ExcludeSchemas), opts.TimeLimit, "", opts.Limit)
 	mock.ExpectQuery(sanitizeQuery(query)).WillReturnRows(rows)
 
 	ch := make(chan prometheus.Metric)
@@ -165,3 +167,72 @@ func TestScrapePerfEventsStatementsMySQL8028(t *testing.T) {
 		t.Errorf("there were unfulfilled expectations: %s", err)
 	}
 }
+
+func TestScrapePerfEventsStatementsNativeHistogram(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error opening a stub database connection: %s", err)
+	}
+	defer db.Close()
+
+	inst := &instance{
+		db:      db,
+		flavor:  FlavorMySQL,
+		version: semver.MustParse("8.0.28"),
+	}
+
+	opts := DefaultPerfEventsStatementsOptions()
+	opts.NativeHistograms = NativeHistogramOptions{Enabled: true, Schema: 0}
+
+	columns := []string{
+		"SCHEMA_NAME", "DIGEST", "DIGEST_TEXT",
+		"COUNT_STAR", "SUM_TIMER_WAIT",
+		"SUM_LOCK_TIME", "SUM_CPU_TIME",
+		"SUM_ERRORS", "SUM_WARNINGS",
+		"SUM_ROWS_AFFECTED", "SUM_ROWS_SENT", "SUM_ROWS_EXAMINED",
+		"SUM_CREATED_TMP_DISK_TABLES", "SUM_CREATED_TMP_TABLES", "SUM_SORT_MERGE_PASSES",
+		"SUM_SORT_ROWS", "SUM_NO_INDEX_USED",
+		"QUANTILE_95", "QUANTILE_99", "QUANTILE_999",
+	}
+	rows := sqlmock.NewRows(columns).
+		AddRow("db1", "digest1", "SELECT * FROM test", 3, uint64(5e12), 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0)
+	query := fmt.Sprintf(perfEventsStatementsQueryMySQL, opts.DigestTextLimit, buildExcludedSchemasList(opts.ExcludeSchemas), opts.TimeLimit, "", opts.Limit)
+	mock.ExpectQuery(sanitizeQuery(query)).WillReturnRows(rows)
+
+	histogramRows := sqlmock.NewRows([]string{"SCHEMA_NAME", "DIGEST", "BUCKET_TIMER_HIGH", "COUNT_BUCKET"}).
+		AddRow("db1", "digest1", uint64(1e12), 1).
+		AddRow("db1", "digest1", uint64(2e12), 2).
+		AddRow("db2", "digest2", uint64(2e12), 5)
+	mock.ExpectQuery(sanitizeQuery(fmt.Sprintf(perfEventsStatementsHistogramQuery, `\?`))).WithArgs("digest1").WillReturnRows(histogramRows)
+
+	ch := make(chan prometheus.Metric)
+	go func() {
+		if err = (ScrapePerfEventsStatements{Options: &opts}).Scrape(context.Background(), inst, ch, promslog.NewNopLogger()); err != nil {
+			t.Errorf("error calling function on test: %s", err)
+		}
+		close(ch)
+	}()
+
+	var histogram *dto.Histogram
+	for m := range ch {
+		pb := &dto.Metric{}
+		m.Write(pb)
+		if pb.Summary != nil {
+			t.Error("unexpected summary")
+		}
+		if pb.Histogram != nil {
+			histogram = pb.Histogram
+		}
+	}
+
+	convey.Convey("Native histogram of digest latency", t, func() {
+		convey.So(histogram, convey.ShouldNotBeNil)
+		convey.So(histogram.GetSampleCount(), convey.ShouldEqual, 3)
+		convey.So(histogram.GetSampleSum(), convey.ShouldEqual, 5)
+		convey.So(histogram.PositiveDelta, convey.ShouldResemble, []int64{1, 1})
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -59,6 +59,11 @@ func (ScrapePerfEventsWaits) Version() float64 {
 	return 5.5
 }
 
+// Privileges required by the Scraper.
+func (ScrapePerfEventsWaits) Privileges() []Privilege {
+	return []Privilege{privSelectPerfSchema}
+}
+
 // Scrape collects data from database connection and sends it over channel as prometheus metric.
 func (ScrapePerfEventsWaits) Scrape(ctx context.Context, instance *instance, ch chan<- prometheus.Metric, logger *slog.Logger) error {
 	db := instance.getDB()
@@ -93,4 +98,4 @@ func (ScrapePerfEventsWaits) Scrape(ctx context.Context, instance *instance, ch
 }
 
 // check interface
-var _ Scraper = ScrapePerfEventsWaits{}
+var _ PrivilegedScraper = ScrapePerfEventsWaits{}
@@ -68,6 +68,11 @@ func (ScrapePerfFileEvents) Version() float64 {
 	return 5.6
 }
 
+// Privileges required by the Scraper.
+func (ScrapePerfFileEvents) Privileges() []Privilege {
+	return []Privilege{privSelectPerfSchema}
+}
+
 // Scrape collects data from database connection and sends it over channel as prometheus metric.
 func (ScrapePerfFileEvents) Scrape(ctx context.Context, instance *instance, ch chan<- prometheus.Metric, logger *slog.Logger) error {
 	db := instance.getDB()
@@ -130,4 +135,4 @@ func (ScrapePerfFileEvents) Scrape(ctx context.Context, instance *instance, ch c
 }
 
 // check interface
-var _ Scraper = ScrapePerfFileEvents{}
+var _ PrivilegedScraper = ScrapePerfFileEvents{}
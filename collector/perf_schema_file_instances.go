@@ -20,7 +20,6 @@ import (
 	"log/slog"
 	"strings"
 
-	"github.com/alecthomas/kingpin/v2"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
@@ -33,19 +32,6 @@ const perfFileInstancesQuery = `
 	     where FILE_NAME REGEXP ?
 	`
 
-// Tunable flags.
-var (
-	performanceSchemaFileInstancesFilter = kingpin.Flag(
-		"collect.perf_schema.file_instances.filter",
-		"RegEx file_name filter for performance_schema.file_summary_by_instance",
-	).Default(".*").String()
-
-	performanceSchemaFileInstancesRemovePrefix = kingpin.Flag(
-		"collect.perf_schema.file_instances.remove_prefix",
-		"Remove path prefix in performance_schema.file_summary_by_instance",
-	).Default("/var/lib/mysql/").String()
-)
-
 // Metric descriptors.
 var (
 	performanceSchemaFileInstancesBytesDesc = prometheus.NewDesc(
@@ -61,7 +47,31 @@ var (
 )
 
 // ScrapePerfFileInstances collects from `performance_schema.file_summary_by_instance`.
-type ScrapePerfFileInstances struct{}
+type ScrapePerfFileInstances struct {
+	// Options of the scraper, DefaultPerfFileInstancesOptions() if nil.
+	Options *PerfFileInstancesOptions
+}
+
+// PerfFileInstancesOptions configures ScrapePerfFileInstances.
+type PerfFileInstancesOptions struct {
+	// Filter is a regular expression the file names must match.
+	Filter string
+	// RemovePrefix is removed from the file names.
+	RemovePrefix string
+}
+
+// DefaultPerfFileInstancesOptions returns the default options of
+// ScrapePerfFileInstances.
+func DefaultPerfFileInstancesOptions() PerfFileInstancesOptions {
+	return PerfFileInstancesOptions{Filter: ".*", RemovePrefix: "/var/lib/mysql/"}
+}
+
+func (s ScrapePerfFileInstances) options() PerfFileInstancesOptions {
+	if s.Options == nil {
+		return DefaultPerfFileInstancesOptions()
+	}
+	return *s.Options
+}
 
 // Name of the Scraper. Should be unique.
 func (ScrapePerfFileInstances) Name() string {
@@ -78,11 +88,22 @@ func (ScrapePerfFileInstances) Version() float64 {
 	return 5.5
 }
 
+// Privileges required by the Scraper.
+func (ScrapePerfFileInstances) Privileges() []Privilege {
+	return []Privilege{privSelectPerfSchema}
+}
+
+// Cost of the Scraper.
+func (ScrapePerfFileInstances) Cost() ScraperCost {
+	return CostExpensive
+}
+
 // Scrape collects data from database connection and sends it over channel as prometheus metric.
-func (ScrapePerfFileInstances) Scrape(ctx context.Context, instance *instance, ch chan<- prometheus.Metric, logger *slog.Logger) error {
+func (s ScrapePerfFileInstances) Scrape(ctx context.Context, instance *instance, ch chan<- prometheus.Metric, logger *slog.Logger) error {
+	opts := s.options()
 	db := instance.getDB()
 	// Timers here are returned in picoseconds.
-	perfSchemaFileInstancesRows, err := db.QueryContext(ctx, perfFileInstancesQuery, *performanceSchemaFileInstancesFilter)
+	perfSchemaFileInstancesRows, err := db.QueryContext(ctx, perfFileInstancesQuery, opts.Filter)
 	if err != nil {
 		return err
 	}
@@ -103,7 +124,7 @@ func (ScrapePerfFileInstances) Scrape(ctx context.Context, instance *instance, c
 			return err
 		}
 
-		fileName = strings.TrimPrefix(fileName, *performanceSchemaFileInstancesRemovePrefix)
+		fileName = strings.TrimPrefix(fileName, opts.RemovePrefix)
 		ch <- prometheus.MustNewConstMetric(
 			performanceSchemaFileInstancesCountDesc, prometheus.CounterValue, float64(countRead),
 			fileName, eventName, "read",
@@ -126,4 +147,4 @@ func (ScrapePerfFileInstances) Scrape(ctx context.Context, instance *instance, c
 }
 
 // check interface
-var _ Scraper = ScrapePerfFileInstances{}
+var _ PrivilegedScraper = ScrapePerfFileInstances{}
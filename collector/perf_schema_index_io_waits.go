@@ -62,6 +62,16 @@ func (ScrapePerfIndexIOWaits) Version() float64 {
 	return 5.6
 }
 
+// Privileges required by the Scraper.
+func (ScrapePerfIndexIOWaits) Privileges() []Privilege {
+	return []Privilege{privSelectPerfSchema}
+}
+
+// Cost of the Scraper.
+func (ScrapePerfIndexIOWaits) Cost() ScraperCost {
+	return CostExpensive
+}
+
 // Scrape collects data from database connection and sends it over channel as prometheus metric.
 func (ScrapePerfIndexIOWaits) Scrape(ctx context.Context, instance *instance, ch chan<- prometheus.Metric, logger *slog.Logger) error {
 	db := instance.getDB()
@@ -128,4 +138,4 @@ func (ScrapePerfIndexIOWaits) Scrape(ctx context.Context, instance *instance, ch
 }
 
 // check interface
-var _ Scraper = ScrapePerfIndexIOWaits{}
+var _ PrivilegedScraper = ScrapePerfIndexIOWaits{}
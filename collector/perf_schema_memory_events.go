@@ -20,7 +20,6 @@ import (
 	"log/slog"
 	"strings"
 
-	"github.com/alecthomas/kingpin/v2"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
@@ -32,14 +31,6 @@ const perfMemoryEventsQuery = `
 		where COUNT_ALLOC > 0;
 `
 
-// Tunable flags.
-var (
-	performanceSchemaMemoryEventsRemovePrefix = kingpin.Flag(
-		"collect.perf_schema.memory_events.remove_prefix",
-		"Remove instrument prefix in performance_schema.memory_summary_global_by_event_name",
-	).Default("memory/").String()
-)
-
 // Metric descriptors.
 var (
 	performanceSchemaMemoryBytesAllocDesc = prometheus.NewDesc(
@@ -60,7 +51,29 @@ var (
 )
 
 // ScrapePerfMemoryEvents collects from `performance_schema.memory_summary_global_by_event_name`.
-type ScrapePerfMemoryEvents struct{}
+type ScrapePerfMemoryEvents struct {
+	// Options of the scraper, DefaultPerfMemoryEventsOptions() if nil.
+	Options *PerfMemoryEventsOptions
+}
+
+// PerfMemoryEventsOptions configures ScrapePerfMemoryEvents.
+type PerfMemoryEventsOptions struct {
+	// RemovePrefix is removed from the instrument names.
+	RemovePrefix string
+}
+
+// DefaultPerfMemoryEventsOptions returns the default options of
+// ScrapePerfMemoryEvents.
+func DefaultPerfMemoryEventsOptions() PerfMemoryEventsOptions {
+	return PerfMemoryEventsOptions{RemovePrefix: "memory/"}
+}
+
+func (s ScrapePerfMemoryEvents) options() PerfMemoryEventsOptions {
+	if s.Options == nil {
+		return DefaultPerfMemoryEventsOptions()
+	}
+	return *s.Options
+}
 
 // Name of the Scraper. Should be unique.
 func (ScrapePerfMemoryEvents) Name() string {
@@ -77,8 +90,14 @@ func (ScrapePerfMemoryEvents) Version() float64 {
 	return 5.7
 }
 
+// Privileges required by the Scraper.
+func (ScrapePerfMemoryEvents) Privileges() []Privilege {
+	return []Privilege{privSelectPerfSchema}
+}
+
 // Scrape collects data from database connection and sends it over channel as prometheus metric.
-func (ScrapePerfMemoryEvents) Scrape(ctx context.Context, instance *instance, ch chan<- prometheus.Metric, logger *slog.Logger) error {
+func (s ScrapePerfMemoryEvents) Scrape(ctx context.Context, instance *instance, ch chan<- prometheus.Metric, logger *slog.Logger) error {
+	opts := s.options()
 	db := instance.getDB()
 	perfSchemaMemoryEventsRows, err := db.QueryContext(ctx, perfMemoryEventsQuery)
 	if err != nil {
@@ -100,7 +119,7 @@ func (ScrapePerfMemoryEvents) Scrape(ctx context.Context, instance *instance, ch
 			return err
 		}
 
-		eventName := strings.TrimPrefix(eventName, *performanceSchemaMemoryEventsRemovePrefix)
+		eventName := strings.TrimPrefix(eventName, opts.RemovePrefix)
 		ch <- prometheus.MustNewConstMetric(
 			performanceSchemaMemoryBytesAllocDesc, prometheus.CounterValue, float64(bytesAlloc), eventName,
 		)
@@ -115,4 +134,4 @@ func (ScrapePerfMemoryEvents) Scrape(ctx context.Context, instance *instance, ch
 }
 
 // check interface
-var _ Scraper = ScrapePerfMemoryEvents{}
+var _ PrivilegedScraper = ScrapePerfMemoryEvents{}
@@ -99,6 +99,11 @@ func (ScrapePerfReplicationApplierStatsByWorker) Version() float64 {
 	return 8.0
 }
 
+// Privileges required by the Scraper.
+func (ScrapePerfReplicationApplierStatsByWorker) Privileges() []Privilege {
+	return []Privilege{privSelectPerfSchema}
+}
+
 // Scrape collects data from database connection and sends it over channel as prometheus metric.
 func (ScrapePerfReplicationApplierStatsByWorker) Scrape(ctx context.Context, instance *instance, ch chan<- prometheus.Metric, logger *slog.Logger) error {
 	db := instance.getDB()
@@ -234,4 +239,4 @@ func (ScrapePerfReplicationApplierStatsByWorker) Scrape(ctx context.Context, ins
 }
 
 // check interface
-var _ Scraper = ScrapePerfReplicationApplierStatsByWorker{}
+var _ PrivilegedScraper = ScrapePerfReplicationApplierStatsByWorker{}
@@ -78,6 +78,11 @@ func (ScrapePerfReplicationGroupMemberStats) Version() float64 {
 	return 5.7
 }
 
+// Privileges required by the Scraper.
+func (ScrapePerfReplicationGroupMemberStats) Privileges() []Privilege {
+	return []Privilege{privSelectPerfSchema}
+}
+
 // Scrape collects data from database connection and sends it over channel as prometheus metric.
 func (ScrapePerfReplicationGroupMemberStats) Scrape(ctx context.Context, instance *instance, ch chan<- prometheus.Metric, logger *slog.Logger) error {
 	db := instance.getDB()
@@ -116,4 +121,4 @@ func (ScrapePerfReplicationGroupMemberStats) Scrape(ctx context.Context, instanc
 }
 
 // check interface
-var _ Scraper = ScrapePerfReplicationGroupMemberStats{}
+var _ PrivilegedScraper = ScrapePerfReplicationGroupMemberStats{}
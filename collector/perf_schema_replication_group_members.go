@@ -44,6 +44,11 @@ func (ScrapePerfReplicationGroupMembers) Version() float64 {
 	return 5.7
 }
 
+// Privileges required by the Scraper.
+func (ScrapePerfReplicationGroupMembers) Privileges() []Privilege {
+	return []Privilege{privSelectPerfSchema}
+}
+
 // Scrape collects data from database connection and sends it over channel as prometheus metric.
 func (ScrapePerfReplicationGroupMembers) Scrape(ctx context.Context, instance *instance, ch chan<- prometheus.Metric, logger *slog.Logger) error {
 	db := instance.getDB()
@@ -91,4 +96,4 @@ func (ScrapePerfReplicationGroupMembers) Scrape(ctx context.Context, instance *i
 }
 
 // check interface
-var _ Scraper = ScrapePerfReplicationGroupMembers{}
+var _ PrivilegedScraper = ScrapePerfReplicationGroupMembers{}
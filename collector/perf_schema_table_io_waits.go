@@ -63,6 +63,16 @@ func (ScrapePerfTableIOWaits) Version() float64 {
 	return 5.6
 }
 
+// Privileges required by the Scraper.
+func (ScrapePerfTableIOWaits) Privileges() []Privilege {
+	return []Privilege{privSelectPerfSchema}
+}
+
+// Cost of the Scraper.
+func (ScrapePerfTableIOWaits) Cost() ScraperCost {
+	return CostExpensive
+}
+
 // Scrape collects data from database connection and sends it over channel as prometheus metric.
 func (ScrapePerfTableIOWaits) Scrape(ctx context.Context, instance *instance, ch chan<- prometheus.Metric, logger *slog.Logger) error {
 	db := instance.getDB()
@@ -122,4 +132,4 @@ func (ScrapePerfTableIOWaits) Scrape(ctx context.Context, instance *instance, ch
 }
 
 // check interface
-var _ Scraper = ScrapePerfTableIOWaits{}
+var _ PrivilegedScraper = ScrapePerfTableIOWaits{}
@@ -92,6 +92,11 @@ func (ScrapePerfTableLockWaits) Version() float64 {
 	return 5.6
 }
 
+// Privileges required by the Scraper.
+func (ScrapePerfTableLockWaits) Privileges() []Privilege {
+	return []Privilege{privSelectPerfSchema}
+}
+
 // Scrape collects data from database connection and sends it over channel as prometheus metric.
 func (ScrapePerfTableLockWaits) Scrape(ctx context.Context, instance *instance, ch chan<- prometheus.Metric, logger *slog.Logger) error {
 	db := instance.getDB()
@@ -238,4 +243,4 @@ func (ScrapePerfTableLockWaits) Scrape(ctx context.Context, instance *instance,
 }
 
 // check interface
-var _ Scraper = ScrapePerfTableLockWaits{}
+var _ PrivilegedScraper = ScrapePerfTableLockWaits{}
@@ -0,0 +1,118 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Hashing and redaction of sensitive label values.
+
+package collector
+
+import (
+	"fmt"
+	"slices"
+)
+
+// LabelClass groups labels holding the same kind of sensitive value.
+type LabelClass string
+
+// Label classes.
+const (
+	LabelClassUser       LabelClass = "user"
+	LabelClassHost       LabelClass = "host"
+	LabelClassSchema     LabelClass = "schema"
+	LabelClassTable      LabelClass = "table"
+	LabelClassDigestText LabelClass = "digest_text"
+)
+
+// LabelClasses lists all label classes.
+var LabelClasses = []LabelClass{LabelClassUser, LabelClassHost, LabelClassSchema, LabelClassTable, LabelClassDigestText}
+
+// sensitiveLabels maps the names of the labels set by the collectors to their
+// class.
+var sensitiveLabels = map[string]LabelClass{
+	"user":            LabelClassUser,
+	"mysql_user":      LabelClassUser,
+	"host":            LabelClassHost,
+	"client_host":     LabelClassHost,
+	"client":          LabelClassHost,
+	"hostmask":        LabelClassHost,
+	"master_host":     LabelClassHost,
+	"slave_host":      LabelClassHost,
+	"schema":          LabelClassSchema,
+	"table":           LabelClassTable,
+	"name":            LabelClassTable,
+	"tablespace_name": LabelClassTable,
+	"file_name":       LabelClassTable,
+	"digest_text":     LabelClassDigestText,
+}
+
+// PrivacyMode is how sensitive label values are protected.
+type PrivacyMode string
+
+const (
+	// PrivacyHash replaces values by a stable hash, so that series stay
+	// distinct and joinable across metrics and scrapes.
+	PrivacyHash PrivacyMode = "hash"
+	// PrivacyRedact replaces values by "redacted". Series that become
+	// identical are sent once.
+	PrivacyRedact PrivacyMode = "redact"
+)
+
+// redactedValue replaces redacted label values.
+const redactedValue = "redacted"
+
+// Privacy hashes or redacts the values of sensitive labels.
+type Privacy struct {
+	// Modes maps the classes of labels to protect to how they are
+	// protected.
+	Modes map[LabelClass]PrivacyMode
+	// Salt is hashed with the values, so that short values such as user
+	// names cannot be recovered by hashing candidates.
+	Salt string
+}
+
+// ParseLabelClass returns the label class with the given name.
+func ParseLabelClass(name string) (LabelClass, error) {
+	if c := LabelClass(name); slices.Contains(LabelClasses, c) {
+		return c, nil
+	}
+	return "", fmt.Errorf("unknown label class %q", name)
+}
+
+func (p Privacy) enabled() bool {
+	return len(p.Modes) > 0
+}
+
+// redacts reports whether some values are redacted rather than hashed.
+func (p Privacy) redacts() bool {
+	for _, mode := range p.Modes {
+		if mode == PrivacyRedact {
+			return true
+		}
+	}
+	return false
+}
+
+// protect returns the value of the label, hashed or redacted if it is
+// sensitive, and whether it changed.
+func (p Privacy) protect(label, value string) (string, bool) {
+	class, ok := sensitiveLabels[label]
+	if !ok || value == "" {
+		return value, false
+	}
+	switch p.Modes[class] {
+	case PrivacyHash:
+		return hashLabelValue(p.Salt + value), true
+	case PrivacyRedact:
+		return redactedValue, true
+	}
+	return value, false
+}
@@ -0,0 +1,249 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Privileges required by scrapers and checks against `SHOW GRANTS`.
+
+package collector
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const showGrantsQuery = `SHOW GRANTS`
+
+// Privilege is a privilege on a database object, e.g. SELECT on performance_schema.*.
+type Privilege struct {
+	Name   string
+	Object string
+}
+
+func (p Privilege) String() string {
+	return p.Name + " ON " + p.Object
+}
+
+// PrivilegedScraper is a Scraper that declares the privileges it requires.
+type PrivilegedScraper interface {
+	Scraper
+
+	// Privileges required to run the Scraper.
+	Privileges() []Privilege
+}
+
+// Commonly required privileges.
+var (
+	privProcess           = Privilege{Name: "PROCESS", Object: "*.*"}
+	privReplicationClient = Privilege{Name: "REPLICATION CLIENT", Object: "*.*"}
+	privReplicationSlave  = Privilege{Name: "REPLICATION SLAVE", Object: "*.*"}
+	privSelectAll         = Privilege{Name: "SELECT", Object: "*.*"}
+	privSelectPerfSchema  = Privilege{Name: "SELECT", Object: "performance_schema.*"}
+)
+
+// privilegeAliases lists privileges that imply another one, e.g. MariaDB 10.5
+// split REPLICATION CLIENT into BINLOG MONITOR and SLAVE MONITOR.
+var privilegeAliases = map[string][]string{
+	"REPLICATION CLIENT": {"BINLOG MONITOR", "SLAVE MONITOR", "REPLICA MONITOR"},
+	"REPLICATION SLAVE":  {"REPLICATION REPLICA"},
+}
+
+var missingPrivilegeDesc = prometheus.NewDesc(
+	prometheus.BuildFQName(namespace, exporter, "privilege_missing"),
+	"Whether a privilege required by a collector is missing from SHOW GRANTS.",
+	[]string{"collector", "privilege", "object"}, nil,
+)
+
+// RequiredPrivileges returns the privileges required by a scraper.
+func RequiredPrivileges(scraper Scraper) []Privilege {
+	if s, ok := scraper.(PrivilegedScraper); ok {
+		return s.Privileges()
+	}
+	return nil
+}
+
+// quoteString returns s as a single-quoted SQL string literal.
+func quoteString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return "'" + strings.ReplaceAll(s, "'", `\'`) + "'"
+}
+
+// quoteIdentifier returns s as a backtick-quoted SQL identifier.
+func quoteIdentifier(s string) string {
+	return "`" + strings.ReplaceAll(s, "`", "``") + "`"
+}
+
+// quoteObject returns the object of a privilege as quoted in GRANT. The LIKE
+// wildcards of database level grants are escaped, so that they only apply to
+// the database itself.
+func quoteObject(object string) string {
+	if object == "*.*" {
+		return object
+	}
+	db, table, _ := strings.Cut(object, ".")
+	if table == "*" {
+		return quoteIdentifier(strings.NewReplacer("_", `\_`, "%", `\%`).Replace(db)) + ".*"
+	}
+	return quoteIdentifier(db) + "." + quoteIdentifier(table)
+}
+
+// GrantStatements returns the CREATE USER and GRANT statements giving user
+// the minimal privileges required by the scrapers.
+func GrantStatements(user, host, password string, scrapers []Scraper) []string {
+	account := quoteString(user) + "@" + quoteString(host)
+	byObject := map[string][]string{}
+	for _, scraper := range scrapers {
+		for _, p := range RequiredPrivileges(scraper) {
+			if !slices.Contains(byObject[p.Object], p.Name) {
+				byObject[p.Object] = append(byObject[p.Object], p.Name)
+			}
+		}
+	}
+
+	statements := []string{
+		fmt.Sprintf("CREATE USER IF NOT EXISTS %s IDENTIFIED BY %s WITH MAX_USER_CONNECTIONS 3;", account, quoteString(password)),
+	}
+	objects := make([]string, 0, len(byObject))
+	for object := range byObject {
+		objects = append(objects, object)
+	}
+	// Global privileges first.
+	slices.SortFunc(objects, func(a, b string) int {
+		if (a == "*.*") != (b == "*.*") {
+			if a == "*.*" {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a, b)
+	})
+	for _, object := range objects {
+		names := byObject[object]
+		slices.Sort(names)
+		statements = append(statements, fmt.Sprintf("GRANT %s ON %s TO %s;", strings.Join(names, ", "), quoteObject(object), account))
+	}
+	return statements
+}
+
+var grantRE = regexp.MustCompile(`(?i)^GRANT (.+?) ON (?:TABLE |PROCEDURE |FUNCTION )?(\S+) TO `)
+
+// grants is the set of privileges parsed from SHOW GRANTS.
+type grants []Privilege
+
+// parseGrants parses the output of SHOW GRANTS. Role grants and column
+// privileges are ignored. Escaped wildcards, e.g. \_, are kept in the objects.
+func parseGrants(lines []string) grants {
+	var g grants
+	for _, line := range lines {
+		match := grantRE.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		object := strings.ReplaceAll(match[2], "`", "")
+		for _, name := range strings.Split(match[1], ",") {
+			name = strings.ToUpper(strings.TrimSpace(name))
+			if strings.Contains(name, "(") || strings.Contains(name, ")") {
+				continue
+			}
+			if name == "ALL" {
+				name = "ALL PRIVILEGES"
+			}
+			g = append(g, Privilege{Name: name, Object: object})
+		}
+	}
+	return g
+}
+
+// objectCovers reports whether a grant on object applies to target.
+func objectCovers(object, target string) bool {
+	if object == "*.*" || strings.EqualFold(object, target) {
+		return true
+	}
+	grantDB, grantTable, _ := strings.Cut(object, ".")
+	targetDB, targetTable, _ := strings.Cut(target, ".")
+	if grantTable != "*" {
+		return strings.EqualFold(grantDB, targetDB) && strings.EqualFold(grantTable, targetTable)
+	}
+	// Database level grants may use the LIKE wildcards _ and %, unless
+	// escaped with a backslash.
+	var pattern strings.Builder
+	pattern.WriteString("(?i)^")
+	for i := 0; i < len(grantDB); i++ {
+		switch c := grantDB[i]; {
+		case c == '\\' && i+1 < len(grantDB):
+			i++
+			pattern.WriteString(regexp.QuoteMeta(grantDB[i : i+1]))
+		case c == '%':
+			pattern.WriteString(".*")
+		case c == '_':
+			pattern.WriteString(".")
+		default:
+			pattern.WriteString(regexp.QuoteMeta(grantDB[i : i+1]))
+		}
+	}
+	pattern.WriteString("$")
+	matched, err := regexp.MatchString(pattern.String(), targetDB)
+	return err == nil && matched
+}
+
+// has reports whether the grants include the privilege.
+func (g grants) has(p Privilege) bool {
+	names := append([]string{p.Name, "ALL PRIVILEGES"}, privilegeAliases[p.Name]...)
+	for _, granted := range g {
+		if slices.Contains(names, granted.Name) && objectCovers(granted.Object, p.Object) {
+			return true
+		}
+	}
+	return false
+}
+
+// queryGrants returns the grants of the current user.
+func queryGrants(ctx context.Context, instance *instance) (grants, error) {
+	rows, err := instance.getDB().QueryContext(ctx, showGrantsQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var lines []string
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err != nil {
+			return nil, err
+		}
+		lines = append(lines, line)
+	}
+	return parseGrants(lines), rows.Err()
+}
+
+// checkPrivileges compares the grants of the current user against the
+// privileges required by the scrapers.
+func checkPrivileges(ctx context.Context, instance *instance, scrapers []Scraper, ch chan<- prometheus.Metric) error {
+	g, err := queryGrants(ctx, instance)
+	if err != nil {
+		return err
+	}
+	for _, scraper := range scrapers {
+		for _, p := range RequiredPrivileges(scraper) {
+			missing := 0.0
+			if !g.has(p) {
+				missing = 1.0
+			}
+			ch <- prometheus.MustNewConstMetric(missingPrivilegeDesc, prometheus.GaugeValue, missing, scraper.Name(), p.Name, p.Object)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/go-cmp/cmp"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/smartystreets/goconvey/convey"
+)
+
+func TestGrantStatements(t *testing.T) {
+	got := GrantStatements("exporter", "%", "secret", []Scraper{
+		ScrapeGlobalStatus{},
+		ScrapeSlaveStatus{},
+		ScrapeProcesslist{},
+		ScrapePerfEventsStatements{},
+		ScrapePerfTableIOWaits{},
+		ScrapeUser{},
+	})
+	want := []string{
+		"CREATE USER IF NOT EXISTS 'exporter'@'%' IDENTIFIED BY 'secret' WITH MAX_USER_CONNECTIONS 3;",
+		"GRANT PROCESS, REPLICATION CLIENT ON *.* TO 'exporter'@'%';",
+		"GRANT SELECT ON `mysql`.`user` TO 'exporter'@'%';",
+		"GRANT SELECT ON `performance\\_schema`.* TO 'exporter'@'%';",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected statements (-want +got):\n%s", diff)
+	}
+}
+
+func TestGrantStatementsQuoting(t *testing.T) {
+	got := GrantStatements("o'brien", `10.0.0.%`, `it's\'; DROP USER root; --`, []Scraper{ScrapeSlaveStatus{}})
+	want := []string{
+		`CREATE USER IF NOT EXISTS 'o\'brien'@'10.0.0.%' IDENTIFIED BY 'it\'s\\\'; DROP USER root; --' WITH MAX_USER_CONNECTIONS 3;`,
+		`GRANT REPLICATION CLIENT ON *.* TO 'o\'brien'@'10.0.0.%';`,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected statements (-want +got):\n%s", diff)
+	}
+}
+
+func TestGrantStatementsIdentifiers(t *testing.T) {
+	got := GrantStatements("exporter", "%", "secret", []Scraper{
+		ScrapeHeartbeat{Options: &HeartbeatOptions{Database: "my-db", Table: "beat`s"}},
+	})
+	want := "GRANT SELECT ON `my-db`.`beat``s` TO 'exporter'@'%';"
+	if !slices.Contains(got, want) {
+		t.Errorf("missing %s in %q", want, got)
+	}
+}
+
+func TestGrantsHas(t *testing.T) {
+	g := parseGrants([]string{
+		"GRANT PROCESS, BINLOG MONITOR ON *.* TO `exporter`@`%`",
+		"GRANT SELECT ON `performance\\_schema`.* TO `exporter`@`%`",
+		"GRANT SELECT (`User`, `Host`) ON `mysql`.`user` TO `exporter`@`%`",
+		"GRANT ALL PRIVILEGES ON `heart%`.* TO `exporter`@`%` WITH GRANT OPTION",
+		"GRANT `monitoring`@`%` TO `exporter`@`%`",
+		"GRANT SELECT ON `app\\_db`.* TO `exporter`@`%`",
+		"GRANT SELECT ON `tenant_`.* TO `exporter`@`%`",
+	})
+	tests := []struct {
+		privilege Privilege
+		want      bool
+	}{
+		{privProcess, true},
+		{privReplicationClient, true},
+		{privSelectPerfSchema, true},
+		{Privilege{Name: "SELECT", Object: "performance_schema.events_statements_summary_by_digest"}, true},
+		{Privilege{Name: "SELECT", Object: "mysql.user"}, false},
+		{Privilege{Name: "SELECT", Object: "heartbeat.heartbeat"}, true},
+		{Privilege{Name: "SELECT", Object: "app_db.t"}, true},
+		{Privilege{Name: "SELECT", Object: "appxdb.t"}, false},
+		{Privilege{Name: "SELECT", Object: "tenant1.t"}, true},
+		{Privilege{Name: "SELECT", Object: "tenant12.t"}, false},
+		{privSelectAll, false},
+		{privReplicationSlave, false},
+	}
+	for _, tt := range tests {
+		if got := g.has(tt.privilege); got != tt.want {
+			t.Errorf("has(%s) = %t, want %t", tt.privilege, got, tt.want)
+		}
+	}
+}
+
+func TestCheckPrivileges(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error opening a stub database connection: %s", err)
+	}
+	defer db.Close()
+	inst := &instance{db: db}
+
+	rows := sqlmock.NewRows([]string{"Grants for exporter@%"}).
+		AddRow("GRANT PROCESS ON *.* TO `exporter`@`%`")
+	mock.ExpectQuery(sanitizeQuery(showGrantsQuery)).WillReturnRows(rows)
+
+	ch := make(chan prometheus.Metric)
+	go func() {
+		if err = checkPrivileges(context.Background(), inst, []Scraper{ScrapeProcesslist{}, ScrapeSlaveStatus{}}, ch); err != nil {
+			t.Errorf("error calling function on test: %s", err)
+		}
+		close(ch)
+	}()
+
+	expected := []MetricResult{
+		{labels: labelMap{"collector": "info_schema.processlist", "privilege": "PROCESS", "object": "*.*"}, value: 0, metricType: dto.MetricType_GAUGE},
+		{labels: labelMap{"collector": "slave_status", "privilege": "REPLICATION CLIENT", "object": "*.*"}, value: 1, metricType: dto.MetricType_GAUGE},
+	}
+	convey.Convey("Metrics comparison", t, func() {
+		for _, expect := range expected {
+			got := readMetric(<-ch)
+			convey.So(got, convey.ShouldResemble, expect)
+		}
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled exceptions: %s", err)
+	}
+}
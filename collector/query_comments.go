@@ -0,0 +1,137 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Identifying comments on the exporter's own queries.
+
+package collector
+
+import (
+	"context"
+	"database/sql/driver"
+
+	"github.com/blang/semver/v4"
+)
+
+const (
+	// queryCommentPrefix starts the comment of every query of the exporter.
+	queryCommentPrefix = "/* mysqld_exporter"
+	// programName is sent as the program_name connection attribute.
+	programName = "mysqld_exporter"
+)
+
+// EnableQueryComments prefixes every query with a comment naming the exporter
+// and the collector running it.
+func EnableQueryComments(b bool) ExporterOpt {
+	return func(e *Exporter) {
+		e.queryComments = b
+	}
+}
+
+type collectorNameKey struct{}
+
+// withCollectorName returns a context whose queries are run by the named
+// collector.
+func withCollectorName(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, collectorNameKey{}, name)
+}
+
+// queryComment returns the comment identifying a query run with ctx.
+func queryComment(ctx context.Context) string {
+	if name, ok := ctx.Value(collectorNameKey{}).(string); ok {
+		return queryCommentPrefix + " collector=" + name + " */ "
+	}
+	return queryCommentPrefix + " */ "
+}
+
+// exporterStatementsFilter returns an SQL condition on
+// performance_schema.events_statements_summary_by_digest excluding the
+// exporter's own statements, or "" if they are not excluded or the server
+// does not support it.
+func exporterStatementsFilter(instance *instance, exclude bool) string {
+	if !exclude || instance.flavor != FlavorMySQL || instance.version.LT(semver.MustParse("8.0.3")) {
+		return ""
+	}
+	return "IFNULL(QUERY_SAMPLE_TEXT, '') NOT LIKE '" + queryCommentPrefix + "%'"
+}
+
+// commentingConnector prefixes the queries on its connections with a comment.
+type commentingConnector struct {
+	driver.Connector
+}
+
+func (c commentingConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &commentingConn{wrappedConn{conn}}, nil
+}
+
+type commentingConn struct {
+	wrappedConn
+}
+
+func (c *commentingConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	return queryConn(ctx, c.Conn, queryComment(ctx)+query, args)
+}
+
+func (c *commentingConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if execer, ok := c.Conn.(driver.ExecerContext); ok {
+		return execer.ExecContext(ctx, queryComment(ctx)+query, args)
+	}
+	return nil, driver.ErrSkip
+}
+
+// PrepareContext comments the statements database/sql prepares when a query
+// or exec returns driver.ErrSkip.
+func (c *commentingConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	return c.wrappedConn.PrepareContext(ctx, queryComment(ctx)+query)
+}
+
+func (c *commentingConn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+// wrappedConn forwards the optional interfaces of a connection that wrappers
+// do not change.
+type wrappedConn struct {
+	driver.Conn
+}
+
+func (c wrappedConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	if preparer, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		return preparer.PrepareContext(ctx, query)
+	}
+	return c.Conn.Prepare(query)
+}
+
+func (c wrappedConn) Ping(ctx context.Context) error {
+	if pinger, ok := c.Conn.(driver.Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+	return nil
+}
+
+func (c wrappedConn) ResetSession(ctx context.Context) error {
+	if resetter, ok := c.Conn.(driver.SessionResetter); ok {
+		return resetter.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c wrappedConn) IsValid() bool {
+	if validator, ok := c.Conn.(driver.Validator); ok {
+		return validator.IsValid()
+	}
+	return true
+}
@@ -0,0 +1,125 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/blang/semver/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/promslog"
+	"github.com/smartystreets/goconvey/convey"
+)
+
+func TestQueryComments(t *testing.T) {
+	mockDB, mock, err := sqlmock.NewWithDSN("query_comments", sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	if err != nil {
+		t.Fatalf("error opening a stub database connection: %s", err)
+	}
+	defer mockDB.Close()
+	db := sql.OpenDB(commentingConnector{dsnConnector{dsn: "query_comments", drv: mockDB.Driver()}})
+	defer db.Close()
+
+	mock.ExpectQuery("/* mysqld_exporter */ SELECT @@version;").
+		WillReturnRows(sqlmock.NewRows([]string{"@@version"}).AddRow("8.0.36"))
+	mock.ExpectQuery("/* mysqld_exporter collector=global_status */ " + globalStatusQuery).
+		WillReturnRows(sqlmock.NewRows([]string{"Variable_name", "Value"}).AddRow("Uptime", "10"))
+
+	convey.Convey("Queries are prefixed with the name of their collector", t, func() {
+		var version string
+		convey.So(db.QueryRowContext(context.Background(), "SELECT @@version;").Scan(&version), convey.ShouldBeNil)
+
+		ch := make(chan prometheus.Metric)
+		go func() {
+			ctx := withCollectorName(context.Background(), ScrapeGlobalStatus{}.Name())
+			if err := (ScrapeGlobalStatus{}).Scrape(ctx, &instance{db: db}, ch, promslog.NewNopLogger()); err != nil {
+				t.Errorf("error calling function on test: %s", err)
+			}
+			close(ch)
+		}()
+		for range ch {
+		}
+		convey.So(mock.ExpectationsWereMet(), convey.ShouldBeNil)
+	})
+}
+
+// preparingConn records the statements prepared after an exec returned
+// driver.ErrSkip.
+type preparingConn struct {
+	skipConn
+	prepared []string
+}
+
+func (c *preparingConn) Prepare(query string) (driver.Stmt, error) {
+	c.prepared = append(c.prepared, query)
+	return nil, errors.New("not implemented")
+}
+
+func (c *preparingConn) Close() error { return nil }
+
+type preparingConnector struct {
+	conn *preparingConn
+}
+
+func (c preparingConnector) Connect(context.Context) (driver.Conn, error) { return c.conn, nil }
+func (preparingConnector) Driver() driver.Driver                          { return nil }
+
+func TestQueryCommentsPrepare(t *testing.T) {
+	conn := &preparingConn{}
+	db := sql.OpenDB(commentingConnector{preparingConnector{conn}})
+	defer db.Close()
+
+	convey.Convey("Statements prepared after driver.ErrSkip keep the comment", t, func() {
+		ctx := withCollectorName(context.Background(), "heartbeat")
+		_, err := db.ExecContext(ctx, "SET SESSION sql_log_bin = ?", 0)
+		convey.So(err, convey.ShouldNotBeNil)
+		convey.So(conn.prepared, convey.ShouldResemble, []string{"/* mysqld_exporter collector=heartbeat */ SET SESSION sql_log_bin = ?"})
+	})
+}
+
+func TestExporterStatementsFilter(t *testing.T) {
+	mysql8 := &instance{flavor: FlavorMySQL, version: semver.MustParse("8.0.36")}
+	mysql57 := &instance{flavor: FlavorMySQL, version: semver.MustParse("5.7.44")}
+	mariadb := &instance{flavor: FlavorMariaDB, version: semver.MustParse("10.11.6")}
+
+	convey.Convey("The exporter's statements are only excluded if enabled and supported", t, func() {
+		convey.So(exporterStatementsFilter(mysql8, false), convey.ShouldBeEmpty)
+		convey.So(exporterStatementsFilter(mysql8, true), convey.ShouldEqual, "IFNULL(QUERY_SAMPLE_TEXT, '') NOT LIKE '/* mysqld_exporter%'")
+		convey.So(exporterStatementsFilter(mysql57, true), convey.ShouldBeEmpty)
+		convey.So(exporterStatementsFilter(mariadb, true), convey.ShouldBeEmpty)
+	})
+
+	convey.Convey("The digest sum query is filtered", t, func() {
+		db, mock, err := sqlmock.New()
+		convey.So(err, convey.ShouldBeNil)
+		defer db.Close()
+		mysql8.db = db
+
+		mock.ExpectQuery(`FROM performance_schema\.events_statements_summary_by_digest\s+WHERE IFNULL\(QUERY_SAMPLE_TEXT, ''\) NOT LIKE '/\* mysqld_exporter%';`).
+			WillReturnRows(sqlmock.NewRows([]string{"SUM_COUNT_STAR"}))
+		ch := make(chan prometheus.Metric)
+		go func() {
+			_ = (ScrapePerfEventsStatementsSum{Options: &PerfEventsStatementsSumOptions{ExcludeExporterStatements: true}}).Scrape(context.Background(), mysql8, ch, promslog.NewNopLogger())
+			close(ch)
+		}()
+		for range ch {
+		}
+		convey.So(mock.ExpectationsWereMet(), convey.ShouldBeNil)
+	})
+}
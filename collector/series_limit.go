@@ -0,0 +1,203 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Limits on the number of series sent by a collector.
+
+package collector
+
+import (
+	"slices"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// otherLabelValue replaces all label values of folded series.
+const otherLabelValue = "other"
+
+var seriesDroppedDesc = prometheus.NewDesc(
+	prometheus.BuildFQName(namespace, exporter, "collector_series_dropped"),
+	"Number of series of a collector dropped or folded by the series limit in the last scrape.",
+	[]string{"collector"}, nil,
+)
+
+// SeriesLimits caps the number of series sent by each collector.
+type SeriesLimits struct {
+	// Default is the limit of collectors not listed in Collectors. Zero
+	// means unlimited.
+	Default int
+	// Collectors maps collector names to their limit.
+	Collectors map[string]int
+	// Fold series over the limit into a single series per metric, with all
+	// label values set to "other", instead of dropping them. Summaries and
+	// native histograms cannot be folded and are always dropped.
+	Fold bool
+}
+
+// SetSeriesLimits limits the number of series sent by each collector.
+func SetSeriesLimits(limits SeriesLimits) ExporterOpt {
+	return func(e *Exporter) {
+		e.seriesLimits = limits
+	}
+}
+
+// limit returns the series limit of a collector, zero if unlimited.
+func (l SeriesLimits) limit(collector string) int {
+	if limit, ok := l.Collectors[collector]; ok {
+		return limit
+	}
+	return l.Default
+}
+
+// enabled reports whether any collector is limited.
+func (l SeriesLimits) enabled() bool {
+	if l.Default > 0 {
+		return true
+	}
+	for _, limit := range l.Collectors {
+		if limit > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// seriesLimiter enforces the series limit of a single scraper run.
+type seriesLimiter struct {
+	limit   int
+	fold    bool
+	series  int
+	dropped int
+	folded  map[string]*foldedMetric
+	// Names of the folded metrics in the order they were first seen.
+	order []string
+}
+
+func newSeriesLimiter(limit int, fold bool) *seriesLimiter {
+	return &seriesLimiter{limit: limit, fold: fold, folded: map[string]*foldedMetric{}}
+}
+
+// admit reports whether the metric is within the limit. Metrics over the limit
+// are counted as dropped and folded if enabled. When folding, metrics whose
+// descriptor cannot be parsed are passed through.
+func (l *seriesLimiter) admit(m prometheus.Metric) bool {
+	if l.limit <= 0 || l.series < l.limit {
+		l.series++
+		return true
+	}
+	if !l.fold {
+		l.dropped++
+		return false
+	}
+	name, help, err := descNameAndHelp(m.Desc())
+	if err != nil {
+		return true
+	}
+	l.dropped++
+	l.add(m, name, help)
+	return false
+}
+
+// foldedMetric is the sum of the series of a metric over the limit.
+type foldedMetric struct {
+	desc      *prometheus.Desc
+	labels    int
+	valueType prometheus.ValueType
+	histogram bool
+	value     float64
+	count     uint64
+	buckets   map[float64]uint64
+}
+
+func (l *seriesLimiter) add(m prometheus.Metric, name, help string) {
+	var pb dto.Metric
+	if err := m.Write(&pb); err != nil {
+		return
+	}
+
+	var (
+		valueType prometheus.ValueType
+		histogram bool
+	)
+	switch {
+	case pb.Counter != nil:
+		valueType = prometheus.CounterValue
+	case pb.Gauge != nil:
+		valueType = prometheus.GaugeValue
+	case pb.Untyped != nil:
+		valueType = prometheus.UntypedValue
+	case pb.Histogram != nil && pb.Histogram.Schema == nil:
+		histogram = true
+	default:
+		return
+	}
+
+	f, ok := l.folded[name]
+	if !ok {
+		labelNames := make([]string, 0, len(pb.Label))
+		for _, lp := range pb.Label {
+			labelNames = append(labelNames, lp.GetName())
+		}
+		f = &foldedMetric{
+			desc:      prometheus.NewDesc(name, help, labelNames, nil),
+			labels:    len(labelNames),
+			valueType: valueType,
+			histogram: histogram,
+			buckets:   map[float64]uint64{},
+		}
+		l.folded[name] = f
+		l.order = append(l.order, name)
+	}
+	if f.histogram != histogram || f.valueType != valueType || f.labels != len(pb.Label) {
+		return
+	}
+
+	switch {
+	case pb.Counter != nil:
+		f.value += pb.Counter.GetValue()
+	case pb.Gauge != nil:
+		f.value += pb.Gauge.GetValue()
+	case pb.Untyped != nil:
+		f.value += pb.Untyped.GetValue()
+	default:
+		f.value += pb.Histogram.GetSampleSum()
+		f.count += pb.Histogram.GetSampleCount()
+		for _, b := range pb.Histogram.Bucket {
+			f.buckets[b.GetUpperBound()] += b.GetCumulativeCount()
+		}
+	}
+}
+
+// flush sends the folded series and returns their number.
+func (l *seriesLimiter) flush(ch chan<- prometheus.Metric) int {
+	n := 0
+	for _, name := range l.order {
+		f := l.folded[name]
+		labelValues := slices.Repeat([]string{otherLabelValue}, f.labels)
+		var (
+			m   prometheus.Metric
+			err error
+		)
+		if f.histogram {
+			m, err = prometheus.NewConstHistogram(f.desc, f.count, f.value, f.buckets, labelValues...)
+		} else {
+			m, err = prometheus.NewConstMetric(f.desc, f.valueType, f.value, labelValues...)
+		}
+		if err != nil {
+			continue
+		}
+		ch <- m
+		n++
+	}
+	return n
+}
@@ -0,0 +1,138 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Session settings limiting the impact of the exporter's connection.
+
+package collector
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/blang/semver/v4"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var sessionGuardrailDesc = prometheus.NewDesc(
+	prometheus.BuildFQName(namespace, exporter, "session_guardrail_applied"),
+	"Whether a session guardrail was applied to the connection in the last scrape.",
+	[]string{"guardrail"}, nil,
+)
+
+// SessionGuardrails are session settings applied to the connection after
+// connecting. Zero values leave the server defaults.
+type SessionGuardrails struct {
+	// ReadOnly makes all transactions of the session read only.
+	ReadOnly bool
+	// ReadUncommitted sets the READ UNCOMMITTED isolation level, so that
+	// information_schema scans do not take or wait for row locks.
+	ReadUncommitted bool
+	// InnodbLockWaitTimeout sets innodb_lock_wait_timeout, in seconds.
+	InnodbLockWaitTimeout int
+	// MaxExecutionTime aborts statements running longer, using
+	// max_execution_time on MySQL 5.7.8+ and max_statement_time on MariaDB
+	// 10.1+.
+	MaxExecutionTime time.Duration
+	// DisableBinlog sets sql_log_bin=0, which requires SUPER or
+	// SYSTEM_VARIABLES_ADMIN.
+	DisableBinlog bool
+	// LongQueryTime sets long_query_time.
+	LongQueryTime time.Duration
+	// LogSlowFilter sets log_slow_filter so that scrapes creating temporary
+	// tables or sorting on disk are not logged as slow, on Percona Server and
+	// MariaDB only.
+	LogSlowFilter bool
+}
+
+// SetSessionGuardrails applies session settings after connecting.
+func SetSessionGuardrails(g SessionGuardrails) ExporterOpt {
+	return func(e *Exporter) {
+		e.sessionGuardrails = g
+	}
+}
+
+// enabled reports whether any guardrail is set.
+func (g SessionGuardrails) enabled() bool {
+	return g != SessionGuardrails{}
+}
+
+// sessionGuardrail is a guardrail with the statement applying it to a
+// specific server, or an error if the server does not support it.
+type sessionGuardrail struct {
+	name      string
+	statement string
+	err       error
+}
+
+// statements returns the statements applying the guardrails to the instance.
+func (g SessionGuardrails) statements(instance *instance) []sessionGuardrail {
+	var guardrails []sessionGuardrail
+	add := func(name, statement string, supported bool) {
+		guardrail := sessionGuardrail{name: name, statement: statement}
+		if !supported {
+			guardrail.err = fmt.Errorf("not supported by %s %s", instance.flavor, instance.version)
+		}
+		guardrails = append(guardrails, guardrail)
+	}
+	mariaDB := instance.flavor == FlavorMariaDB
+
+	if g.ReadOnly {
+		add("read_only", "SET SESSION TRANSACTION READ ONLY",
+			mariaDB || instance.version.GTE(semver.MustParse("5.6.5")))
+	}
+	if g.ReadUncommitted {
+		add("read_uncommitted", "SET SESSION TRANSACTION ISOLATION LEVEL READ UNCOMMITTED", true)
+	}
+	if g.InnodbLockWaitTimeout > 0 {
+		add("innodb_lock_wait_timeout", fmt.Sprintf("SET SESSION innodb_lock_wait_timeout = %d", g.InnodbLockWaitTimeout), true)
+	}
+	if g.MaxExecutionTime > 0 {
+		if mariaDB {
+			add("max_execution_time", fmt.Sprintf("SET SESSION max_statement_time = %g", g.MaxExecutionTime.Seconds()),
+				instance.version.GTE(semver.MustParse("10.1.1")))
+		} else {
+			add("max_execution_time", fmt.Sprintf("SET SESSION max_execution_time = %d", g.MaxExecutionTime.Milliseconds()),
+				instance.version.GTE(semver.MustParse("5.7.8")))
+		}
+	}
+	if g.DisableBinlog {
+		add("sql_log_bin", "SET SESSION sql_log_bin = 0", true)
+	}
+	if g.LongQueryTime > 0 {
+		add("long_query_time", fmt.Sprintf("SET SESSION long_query_time = %g", g.LongQueryTime.Seconds()), true)
+	}
+	if g.LogSlowFilter {
+		add("log_slow_filter", "SET SESSION log_slow_filter = 'tmp_table_on_disk,filesort_on_disk'", mariaDB || instance.percona)
+	}
+	return guardrails
+}
+
+// applySessionGuardrails applies the guardrails to the connection of the
+// instance and reports for each whether it was applied. Guardrails that fail,
+// e.g. for lack of privileges, do not fail the scrape.
+func applySessionGuardrails(ctx context.Context, instance *instance, g SessionGuardrails, ch chan<- prometheus.Metric, logger *slog.Logger) {
+	for _, guardrail := range g.statements(instance) {
+		err := guardrail.err
+		if err == nil {
+			_, err = instance.db.ExecContext(ctx, guardrail.statement)
+		}
+		applied := 1.0
+		if err != nil {
+			logger.Debug("Error applying session guardrail", "guardrail", guardrail.name, "err", err)
+			applied = 0.0
+		}
+		ch <- prometheus.MustNewConstMetric(sessionGuardrailDesc, prometheus.GaugeValue, applied, guardrail.name)
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/blang/semver/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/promslog"
+	"github.com/smartystreets/goconvey/convey"
+)
+
+func TestSessionGuardrailStatements(t *testing.T) {
+	g := SessionGuardrails{ReadOnly: true, MaxExecutionTime: 1500 * time.Millisecond, LongQueryTime: 30 * time.Second, LogSlowFilter: true}
+	statements := func(instance *instance) map[string]string {
+		m := map[string]string{}
+		for _, s := range g.statements(instance) {
+			if s.err != nil {
+				m[s.name] = "unsupported"
+			} else {
+				m[s.name] = s.statement
+			}
+		}
+		return m
+	}
+
+	convey.Convey("Guardrails use the variables of the flavor", t, func() {
+		convey.So(statements(&instance{flavor: FlavorMySQL, version: semver.MustParse("8.0.36")}), convey.ShouldResemble, map[string]string{
+			"read_only":          "SET SESSION TRANSACTION READ ONLY",
+			"max_execution_time": "SET SESSION max_execution_time = 1500",
+			"long_query_time":    "SET SESSION long_query_time = 30",
+			"log_slow_filter":    "unsupported",
+		})
+		convey.So(statements(&instance{flavor: FlavorMySQL, version: semver.MustParse("8.0.36"), percona: true}), convey.ShouldResemble, map[string]string{
+			"read_only":          "SET SESSION TRANSACTION READ ONLY",
+			"max_execution_time": "SET SESSION max_execution_time = 1500",
+			"long_query_time":    "SET SESSION long_query_time = 30",
+			"log_slow_filter":    "SET SESSION log_slow_filter = 'tmp_table_on_disk,filesort_on_disk'",
+		})
+		convey.So(statements(&instance{flavor: FlavorMariaDB, version: semver.MustParse("10.11.6")}), convey.ShouldResemble, map[string]string{
+			"read_only":          "SET SESSION TRANSACTION READ ONLY",
+			"max_execution_time": "SET SESSION max_statement_time = 1.5",
+			"long_query_time":    "SET SESSION long_query_time = 30",
+			"log_slow_filter":    "SET SESSION log_slow_filter = 'tmp_table_on_disk,filesort_on_disk'",
+		})
+		convey.So(statements(&instance{flavor: FlavorMySQL, version: semver.MustParse("5.6.51")}), convey.ShouldResemble, map[string]string{
+			"read_only":          "SET SESSION TRANSACTION READ ONLY",
+			"max_execution_time": "unsupported",
+			"long_query_time":    "SET SESSION long_query_time = 30",
+			"log_slow_filter":    "unsupported",
+		})
+	})
+}
+
+func TestApplySessionGuardrailsSkipsLogSlowFilterOnMySQL(t *testing.T) {
+	// Without a database, executing any statement would panic.
+	inst := &instance{flavor: FlavorMySQL, version: semver.MustParse("8.0.36")}
+	ch := make(chan prometheus.Metric)
+	go func() {
+		applySessionGuardrails(context.Background(), inst, SessionGuardrails{LogSlowFilter: true}, ch, promslog.NewNopLogger())
+		close(ch)
+	}()
+
+	convey.Convey("log_slow_filter is not set on Oracle MySQL", t, func() {
+		got := readMetric(<-ch)
+		convey.So(got.labels, convey.ShouldResemble, labelMap{"guardrail": "log_slow_filter"})
+		convey.So(got.value, convey.ShouldEqual, 0)
+	})
+}
+
+func TestApplySessionGuardrails(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("error opening a stub database connection: %s", err)
+	}
+	defer db.Close()
+
+	mock.ExpectExec(`SET SESSION TRANSACTION ISOLATION LEVEL READ UNCOMMITTED`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`SET SESSION innodb_lock_wait_timeout = 5`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`SET SESSION sql_log_bin = 0`).WillReturnError(errors.New("Access denied; you need the SUPER privilege"))
+
+	g := SessionGuardrails{ReadUncommitted: true, InnodbLockWaitTimeout: 5, DisableBinlog: true}
+	inst := &instance{db: db, flavor: FlavorMySQL, version: semver.MustParse("8.0.36")}
+	ch := make(chan prometheus.Metric)
+	go func() {
+		applySessionGuardrails(context.Background(), inst, g, ch, promslog.NewNopLogger())
+		close(ch)
+	}()
+
+	expected := []MetricResult{
+		{labels: labelMap{"guardrail": "read_uncommitted"}, value: 1},
+		{labels: labelMap{"guardrail": "innodb_lock_wait_timeout"}, value: 1},
+		{labels: labelMap{"guardrail": "sql_log_bin"}, value: 0},
+	}
+	convey.Convey("A failing guardrail is reported without failing the others", t, func() {
+		for _, expect := range expected {
+			got := readMetric(<-ch)
+			convey.So(got.labels, convey.ShouldResemble, expect.labels)
+			convey.So(got.value, convey.ShouldEqual, expect.value)
+		}
+	})
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled exceptions: %s", err)
+	}
+}
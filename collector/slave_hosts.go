@@ -62,6 +62,11 @@ func (ScrapeSlaveHosts) Version() float64 {
 	return 5.1
 }
 
+// Privileges required by the Scraper.
+func (ScrapeSlaveHosts) Privileges() []Privilege {
+	return []Privilege{privReplicationSlave}
+}
+
 // Scrape collects data from database connection and sends it over channel as prometheus metric.
 func (ScrapeSlaveHosts) Scrape(ctx context.Context, instance *instance, ch chan<- prometheus.Metric, logger *slog.Logger) error {
 	var (
@@ -143,4 +148,4 @@ func (ScrapeSlaveHosts) Scrape(ctx context.Context, instance *instance, ch chan<
 }
 
 // check interface
-var _ Scraper = ScrapeSlaveHosts{}
+var _ PrivilegedScraper = ScrapeSlaveHosts{}
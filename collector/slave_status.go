@@ -69,6 +69,11 @@ func (ScrapeSlaveStatus) Version() float64 {
 	return 5.1
 }
 
+// Privileges required by the Scraper.
+func (ScrapeSlaveStatus) Privileges() []Privilege {
+	return []Privilege{privReplicationClient}
+}
+
 // Scrape collects data from database connection and sends it over channel as prometheus metric.
 func (ScrapeSlaveStatus) Scrape(ctx context.Context, instance *instance, ch chan<- prometheus.Metric, logger *slog.Logger) error {
 	var (
@@ -181,4 +186,4 @@ func parseMariaDBGtid(ch chan<- prometheus.Metric, name string, value string, ma
 }
 
 // check interface
-var _ Scraper = ScrapeSlaveStatus{}
+var _ PrivilegedScraper = ScrapeSlaveStatus{}
@@ -0,0 +1,318 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Recording and replaying the query results of a scrape.
+
+package collector
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Snapshot holds the results of the queries run by the scrapers against a
+// server, so the scrapers can be run again without the server.
+type Snapshot struct {
+	Target  string          `json:"target"`
+	Version string          `json:"version"`
+	Time    time.Time       `json:"time"`
+	Queries []SnapshotQuery `json:"queries"`
+
+	mu sync.Mutex
+}
+
+// SnapshotQuery is the result of a query. Rows hold the values as returned
+// by the server, nil for NULL.
+type SnapshotQuery struct {
+	Query   string           `json:"query"`
+	Args    []string         `json:"args,omitempty"`
+	Columns []SnapshotColumn `json:"columns,omitempty"`
+	Rows    [][]*string      `json:"rows,omitempty"`
+	Error   string           `json:"error,omitempty"`
+}
+
+// SnapshotColumn describes a column of a result set.
+type SnapshotColumn struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// RecordSnapshot records the result of every query of a scrape in s.
+func RecordSnapshot(s *Snapshot) ExporterOpt {
+	return func(e *Exporter) {
+		e.recordSnapshot = s
+	}
+}
+
+// ReplaySnapshot answers the queries of the scrapers from s instead of
+// connecting to a server.
+func ReplaySnapshot(s *Snapshot) ExporterOpt {
+	return func(e *Exporter) {
+		e.replaySnapshot = s
+	}
+}
+
+func snapshotArgs(args []driver.NamedValue) []string {
+	var values []string
+	for _, arg := range args {
+		values = append(values, snapshotValue(arg.Value))
+	}
+	return values
+}
+
+func snapshotValue(v driver.Value) string {
+	switch v := v.(type) {
+	case []byte:
+		return string(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case time.Time:
+		return v.Format("2006-01-02 15:04:05.999999")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// record adds the result of a query unless the same query was recorded before.
+func (s *Snapshot) record(q SnapshotQuery) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.lookup(q.Query, q.Args) == nil {
+		s.Queries = append(s.Queries, q)
+	}
+}
+
+func (s *Snapshot) lookup(query string, args []string) *SnapshotQuery {
+	for i, q := range s.Queries {
+		if q.Query == query && slices.Equal(q.Args, args) {
+			return &s.Queries[i]
+		}
+	}
+	return nil
+}
+
+// recordingConnector records the results of all queries in a snapshot.
+type recordingConnector struct {
+	driver.Connector
+	snapshot *Snapshot
+}
+
+func (c recordingConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &recordingConn{wrappedConn: wrappedConn{conn}, snapshot: c.snapshot}, nil
+}
+
+type recordingConn struct {
+	wrappedConn
+	snapshot *Snapshot
+}
+
+func (c *recordingConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	q := SnapshotQuery{Query: query, Args: snapshotArgs(args)}
+	if err := c.query(ctx, &q, args); err != nil {
+		q.Error = err.Error()
+		c.snapshot.record(q)
+		return nil, err
+	}
+	c.snapshot.record(q)
+	return &snapshotRows{query: &q}, nil
+}
+
+// query runs a query and reads the whole result into q.
+func (c *recordingConn) query(ctx context.Context, q *SnapshotQuery, args []driver.NamedValue) error {
+	rows, err := queryConn(ctx, c.Conn, q.Query, args)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	typed, _ := rows.(driver.RowsColumnTypeDatabaseTypeName)
+	for i, name := range rows.Columns() {
+		column := SnapshotColumn{Name: name}
+		if typed != nil {
+			column.Type = typed.ColumnTypeDatabaseTypeName(i)
+		}
+		q.Columns = append(q.Columns, column)
+	}
+	values := make([]driver.Value, len(q.Columns))
+	for {
+		if err := rows.Next(values); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		row := make([]*string, len(values))
+		for i, v := range values {
+			if v != nil {
+				s := snapshotValue(v)
+				row[i] = &s
+			}
+		}
+		q.Rows = append(q.Rows, row)
+	}
+}
+
+func (c *recordingConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if execer, ok := c.Conn.(driver.ExecerContext); ok {
+		return execer.ExecContext(ctx, query, args)
+	}
+	return nil, driver.ErrSkip
+}
+
+// queryConn runs a query on conn. If the driver cannot run the query directly,
+// a prepared statement is used and closed with the rows.
+func queryConn(ctx context.Context, conn driver.Conn, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if queryer, ok := conn.(driver.QueryerContext); ok {
+		rows, err := queryer.QueryContext(ctx, query, args)
+		if !errors.Is(err, driver.ErrSkip) {
+			return rows, err
+		}
+	}
+	var (
+		stmt driver.Stmt
+		err  error
+	)
+	if preparer, ok := conn.(driver.ConnPrepareContext); ok {
+		stmt, err = preparer.PrepareContext(ctx, query)
+	} else {
+		stmt, err = conn.Prepare(query)
+	}
+	if err != nil {
+		return nil, err
+	}
+	stmtQueryer, ok := stmt.(driver.StmtQueryContext)
+	if !ok {
+		stmt.Close()
+		return nil, errors.New("driver does not support queries with arguments")
+	}
+	rows, err := stmtQueryer.QueryContext(ctx, args)
+	if err != nil {
+		stmt.Close()
+		return nil, err
+	}
+	return stmtRows{Rows: rows, stmt: stmt}, nil
+}
+
+// stmtRows closes the statement of the rows with the rows.
+type stmtRows struct {
+	driver.Rows
+	stmt driver.Stmt
+}
+
+func (r stmtRows) Close() error {
+	err := r.Rows.Close()
+	r.stmt.Close()
+	return err
+}
+
+func (r stmtRows) ColumnTypeDatabaseTypeName(i int) string {
+	if typed, ok := r.Rows.(driver.RowsColumnTypeDatabaseTypeName); ok {
+		return typed.ColumnTypeDatabaseTypeName(i)
+	}
+	return ""
+}
+
+// replayConnector answers queries from a snapshot.
+type replayConnector struct {
+	snapshot *Snapshot
+}
+
+func (c replayConnector) Connect(context.Context) (driver.Conn, error) {
+	return replayConn{snapshot: c.snapshot}, nil
+}
+
+func (c replayConnector) Driver() driver.Driver {
+	return replayDriver{}
+}
+
+type replayDriver struct{}
+
+func (replayDriver) Open(string) (driver.Conn, error) {
+	return nil, errors.New("snapshot replay cannot open connections by name")
+}
+
+type replayConn struct {
+	snapshot *Snapshot
+}
+
+func (c replayConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	q := c.snapshot.lookup(query, snapshotArgs(args))
+	if q == nil {
+		return nil, fmt.Errorf("query not found in snapshot: %s", query)
+	}
+	if q.Error != "" {
+		return nil, errors.New(q.Error)
+	}
+	return &snapshotRows{query: q}, nil
+}
+
+func (replayConn) ExecContext(context.Context, string, []driver.NamedValue) (driver.Result, error) {
+	return driver.ResultNoRows, nil
+}
+
+func (replayConn) Ping(context.Context) error { return nil }
+
+func (replayConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("prepared statements are not supported by snapshot replay")
+}
+
+func (replayConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("transactions are not supported by snapshot replay")
+}
+
+func (replayConn) Close() error { return nil }
+
+// snapshotRows returns the rows of a recorded query.
+type snapshotRows struct {
+	query *SnapshotQuery
+	next  int
+}
+
+func (r *snapshotRows) Columns() []string {
+	names := make([]string, 0, len(r.query.Columns))
+	for _, c := range r.query.Columns {
+		names = append(names, c.Name)
+	}
+	return names
+}
+
+func (r *snapshotRows) ColumnTypeDatabaseTypeName(i int) string {
+	return r.query.Columns[i].Type
+}
+
+func (r *snapshotRows) Close() error { return nil }
+
+func (r *snapshotRows) Next(dest []driver.Value) error {
+	if r.next >= len(r.query.Rows) {
+		return io.EOF
+	}
+	for i, v := range r.query.Rows[r.next] {
+		if v == nil {
+			dest[i] = nil
+		} else {
+			dest[i] = []byte(*v)
+		}
+	}
+	r.next++
+	return nil
+}
@@ -0,0 +1,109 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/promslog"
+	"github.com/smartystreets/goconvey/convey"
+)
+
+// dsnConnector opens connections of a driver by DSN.
+type dsnConnector struct {
+	dsn string
+	drv driver.Driver
+}
+
+func (c dsnConnector) Connect(context.Context) (driver.Conn, error) { return c.drv.Open(c.dsn) }
+func (c dsnConnector) Driver() driver.Driver                        { return c.drv }
+
+func TestSnapshotRecordReplay(t *testing.T) {
+	mockDB, mock, err := sqlmock.NewWithDSN("snapshot_record_replay")
+	if err != nil {
+		t.Fatalf("error opening a stub database connection: %s", err)
+	}
+	defer mockDB.Close()
+
+	mock.ExpectQuery(sanitizeQuery(globalStatusQuery)).WillReturnRows(
+		sqlmock.NewRows([]string{"Variable_name", "Value"}).
+			AddRow("Com_select", "3").
+			AddRow("Uptime", "10").
+			AddRow("Ssl_version", nil))
+	mock.ExpectQuery(sanitizeQuery(globalVariablesQuery)).WillReturnError(errors.New("access denied"))
+
+	scrape := func(db *sql.DB) ([]MetricResult, []error) {
+		inst := &instance{db: db}
+		var errs []error
+		ch := make(chan prometheus.Metric)
+		go func() {
+			for _, s := range []Scraper{ScrapeGlobalStatus{}, ScrapeGlobalVariables{}} {
+				if err := s.Scrape(context.Background(), inst, ch, promslog.NewNopLogger()); err != nil {
+					errs = append(errs, err)
+				}
+			}
+			close(ch)
+		}()
+		var metrics []MetricResult
+		for m := range ch {
+			metrics = append(metrics, readMetric(m))
+		}
+		return metrics, errs
+	}
+
+	snapshot := &Snapshot{}
+	recordDB := sql.OpenDB(recordingConnector{
+		Connector: dsnConnector{dsn: "snapshot_record_replay", drv: mockDB.Driver()},
+		snapshot:  snapshot,
+	})
+	defer recordDB.Close()
+	recorded, recordErrs := scrape(recordDB)
+
+	convey.Convey("Query results and errors are recorded", t, func() {
+		convey.So(recordErrs, convey.ShouldHaveLength, 1)
+		convey.So(snapshot.Queries, convey.ShouldHaveLength, 2)
+		convey.So(snapshot.Queries[0].Columns[0].Name, convey.ShouldEqual, "Variable_name")
+		convey.So(snapshot.Queries[0].Rows, convey.ShouldHaveLength, 3)
+		convey.So(snapshot.Queries[0].Rows[2][1], convey.ShouldBeNil)
+		convey.So(snapshot.Queries[1].Error, convey.ShouldEqual, "access denied")
+	})
+
+	convey.Convey("Replay returns the recorded metrics and errors", t, func() {
+		data, err := json.Marshal(snapshot)
+		convey.So(err, convey.ShouldBeNil)
+		loaded := &Snapshot{}
+		convey.So(json.Unmarshal(data, loaded), convey.ShouldBeNil)
+
+		replayDB := sql.OpenDB(replayConnector{snapshot: loaded})
+		defer replayDB.Close()
+		replayed, replayErrs := scrape(replayDB)
+		convey.So(replayed, convey.ShouldResemble, recorded)
+		convey.So(replayErrs, convey.ShouldHaveLength, 1)
+		convey.So(replayErrs[0].Error(), convey.ShouldContainSubstring, "access denied")
+
+		_, err = replayDB.Query("SELECT 1")
+		convey.So(err, convey.ShouldNotBeNil)
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled exceptions: %s", err)
+	}
+}
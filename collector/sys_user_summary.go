@@ -97,6 +97,16 @@ func (ScrapeSysUserSummary) Version() float64 {
 	return 5.7
 }
 
+// Privileges required by the Scraper.
+func (ScrapeSysUserSummary) Privileges() []Privilege {
+	return []Privilege{{Name: "SELECT", Object: "sys.*"}, privSelectPerfSchema}
+}
+
+// Cost of the Scraper.
+func (ScrapeSysUserSummary) Cost() ScraperCost {
+	return CostExpensive
+}
+
 // Scrape the information from sys.user_summary, creating a metric for each value of each row, labeled with the user
 func (ScrapeSysUserSummary) Scrape(ctx context.Context, instance *instance, ch chan<- prometheus.Metric, logger *slog.Logger) error {
 
@@ -155,4 +165,4 @@ func (ScrapeSysUserSummary) Scrape(ctx context.Context, instance *instance, ch c
 	return nil
 }
 
-var _ Scraper = ScrapeSysUserSummary{}
+var _ PrivilegedScraper = ScrapeSysUserSummary{}
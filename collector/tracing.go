@@ -0,0 +1,159 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Tracing of connections, scrapers and queries.
+
+package collector
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+const tracerName = "github.com/prometheus/mysqld_exporter/collector"
+
+// Span attribute keys.
+const (
+	attrCollector    = attribute.Key("mysql.collector")
+	attrReturnedRows = attribute.Key("db.response.returned_rows")
+	attrQueryText    = attribute.Key("db.query.text")
+	attrSystemName   = attribute.Key("db.system.name")
+	attrServerAddr   = attribute.Key("server.address")
+)
+
+var noopTracer = noop.NewTracerProvider().Tracer(tracerName)
+
+// SetTracerProvider traces connecting to the target, every scraper and every
+// query with tracers of tp. The spans are children of the span in the
+// context passed to New.
+func SetTracerProvider(tp trace.TracerProvider) ExporterOpt {
+	return func(e *Exporter) {
+		e.tracer = tp.Tracer(tracerName)
+		e.tracing = true
+	}
+}
+
+// endSpan records err, if any, and ends the span.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+type rowCounterKey struct{}
+
+// withRowCounter returns a context in which traced queries add the number of
+// rows they return to n.
+func withRowCounter(ctx context.Context, n *atomic.Int64) context.Context {
+	return context.WithValue(ctx, rowCounterKey{}, n)
+}
+
+// tracingConnector traces all queries on its connections.
+type tracingConnector struct {
+	driver.Connector
+	tracer trace.Tracer
+}
+
+func (c tracingConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &tracingConn{wrappedConn: wrappedConn{conn}, tracer: c.tracer}, nil
+}
+
+type tracingConn struct {
+	wrappedConn
+	tracer trace.Tracer
+}
+
+func (c *tracingConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	ctx, span := c.tracer.Start(ctx, "query", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(
+		attrSystemName.String("mysql"),
+		attrQueryText.String(query),
+	))
+	rows, err := queryConn(ctx, c.Conn, query, args)
+	if err != nil {
+		endSpan(span, err)
+		return nil, err
+	}
+	counter, _ := ctx.Value(rowCounterKey{}).(*atomic.Int64)
+	return &tracingRows{Rows: rows, span: span, counter: counter}, nil
+}
+
+func (c *tracingConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	execer, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	_, span := c.tracer.Start(ctx, "exec", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(
+		attrSystemName.String("mysql"),
+		attrQueryText.String(query),
+	))
+	result, err := execer.ExecContext(ctx, query, args)
+	if errors.Is(err, driver.ErrSkip) {
+		// database/sql falls back to a prepared statement, which is not a
+		// failure of the exec.
+		endSpan(span, nil)
+		return nil, err
+	}
+	endSpan(span, err)
+	return result, err
+}
+
+// tracingRows ends the span of a query when the rows are closed.
+type tracingRows struct {
+	driver.Rows
+	span    trace.Span
+	counter *atomic.Int64
+	rows    int64
+	err     error
+}
+
+func (r *tracingRows) Next(dest []driver.Value) error {
+	err := r.Rows.Next(dest)
+	switch {
+	case err == nil:
+		r.rows++
+	case err != io.EOF:
+		r.err = err
+	}
+	return err
+}
+
+func (r *tracingRows) Close() error {
+	err := r.Rows.Close()
+	r.span.SetAttributes(attrReturnedRows.Int64(r.rows))
+	if r.counter != nil {
+		r.counter.Add(r.rows)
+	}
+	endSpan(r.span, r.err)
+	return err
+}
+
+func (r *tracingRows) ColumnTypeDatabaseTypeName(i int) string {
+	if typed, ok := r.Rows.(driver.RowsColumnTypeDatabaseTypeName); ok {
+		return typed.ColumnTypeDatabaseTypeName(i)
+	}
+	return ""
+}
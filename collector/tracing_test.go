@@ -0,0 +1,131 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/promslog"
+	"github.com/smartystreets/goconvey/convey"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestExporterTracing(t *testing.T) {
+	value := func(s string) *string { return &s }
+	snapshot := &Snapshot{Queries: []SnapshotQuery{
+		{Query: "SELECT @@version;", Columns: []SnapshotColumn{{Name: "@@version"}}, Rows: [][]*string{{value("8.0.36")}}},
+		{
+			Query:   globalStatusQuery,
+			Columns: []SnapshotColumn{{Name: "Variable_name"}, {Name: "Value"}},
+			Rows:    [][]*string{{value("Uptime"), value("10")}, {value("Com_select"), value("3")}},
+		},
+		{Query: globalVariablesQuery, Error: "access denied"},
+	}}
+
+	spans := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(spans))
+	ctx, root := tp.Tracer("test").Start(context.Background(), "root")
+
+	exporter := New(ctx, "user@tcp(db:3306)/", []Scraper{ScrapeGlobalStatus{}, ScrapeGlobalVariables{}}, promslog.NewNopLogger(),
+		ReplaySnapshot(snapshot), SetTracerProvider(tp))
+	ch := make(chan prometheus.Metric)
+	go func() {
+		exporter.Collect(ch)
+		close(ch)
+	}()
+	for range ch {
+	}
+	root.End()
+
+	byName := map[string]tracetest.SpanStub{}
+	var queries []tracetest.SpanStub
+	for _, s := range spans.GetSpans() {
+		if s.Name == "query" {
+			queries = append(queries, s)
+		}
+		byName[s.Name] = s
+	}
+	attrs := func(s tracetest.SpanStub) map[attribute.Key]attribute.Value {
+		m := map[attribute.Key]attribute.Value{}
+		for _, kv := range s.Attributes {
+			m[kv.Key] = kv.Value
+		}
+		return m
+	}
+
+	convey.Convey("Connecting is traced", t, func() {
+		connect := byName["connect"]
+		convey.So(connect.Parent.SpanID(), convey.ShouldEqual, root.SpanContext().SpanID())
+		convey.So(attrs(connect)["server.address"].AsString(), convey.ShouldEqual, "db:3306")
+		convey.So(attrs(connect)["mysql.version"].AsString(), convey.ShouldEqual, "8.0.36")
+	})
+
+	convey.Convey("Every scraper is traced with its row count and error", t, func() {
+		status := byName["scrape global_status"]
+		convey.So(status.Parent.SpanID(), convey.ShouldEqual, root.SpanContext().SpanID())
+		convey.So(attrs(status)["db.response.returned_rows"].AsInt64(), convey.ShouldEqual, 2)
+		convey.So(status.Status.Code, convey.ShouldEqual, codes.Unset)
+
+		variables := byName["scrape global_variables"]
+		convey.So(variables.Status.Code, convey.ShouldEqual, codes.Error)
+	})
+
+	convey.Convey("Every query is traced with its SQL text", t, func() {
+		convey.So(queries, convey.ShouldHaveLength, 3)
+		parents := map[string]string{}
+		for _, q := range queries {
+			for name, s := range byName {
+				if s.SpanContext.SpanID() == q.Parent.SpanID() {
+					parents[attrs(q)["db.query.text"].AsString()] = name
+				}
+			}
+		}
+		convey.So(parents, convey.ShouldResemble, map[string]string{
+			"SELECT @@version;":  "connect",
+			globalStatusQuery:    "scrape global_status",
+			globalVariablesQuery: "scrape global_variables",
+		})
+	})
+}
+
+// skipConn is a connection that cannot exec without preparing a statement.
+type skipConn struct {
+	driver.Conn
+}
+
+func (skipConn) ExecContext(context.Context, string, []driver.NamedValue) (driver.Result, error) {
+	return nil, driver.ErrSkip
+}
+
+func TestTracingConnExecSkip(t *testing.T) {
+	spans := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(spans))
+	conn := &tracingConn{wrappedConn{skipConn{}}, tp.Tracer("test")}
+
+	convey.Convey("ErrSkip is returned unchanged and does not fail the span", t, func() {
+		result, err := conn.ExecContext(context.Background(), "SET SESSION sql_log_bin = 0", nil)
+		convey.So(result, convey.ShouldBeNil)
+		convey.So(err, convey.ShouldEqual, driver.ErrSkip)
+		got := spans.GetSpans()
+		convey.So(got, convey.ShouldHaveLength, 1)
+		convey.So(got[0].Status.Code, convey.ShouldEqual, codes.Unset)
+		convey.So(got[0].Events, convey.ShouldBeEmpty)
+	})
+}
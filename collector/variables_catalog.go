@@ -0,0 +1,86 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Help text catalog for status and system variables.
+
+package collector
+
+import (
+	_ "embed"
+	"fmt"
+	"strings"
+)
+
+//go:embed variables_catalog.tsv
+var variablesCatalogTSV string
+
+// variableInfo describes a status or system variable.
+type variableInfo struct {
+	Help string
+	// Unit of the raw value, e.g. "bytes" or "seconds". Empty if the value
+	// is a count, a ratio or a boolean.
+	Unit string
+}
+
+// help returns the help string for the variable, falling back to generic
+// if the variable is unknown.
+func (v variableInfo) help(generic string) string {
+	if v.Help == "" {
+		return generic
+	}
+	if v.Unit == "" {
+		return v.Help
+	}
+	return fmt.Sprintf("%s Unit: %s.", v.Help, v.Unit)
+}
+
+var (
+	globalStatusCatalog, globalVariablesCatalog = mustParseVariablesCatalog(variablesCatalogTSV)
+)
+
+// parseVariablesCatalog parses the embedded catalog into the entries for
+// SHOW GLOBAL STATUS and SHOW GLOBAL VARIABLES.
+func parseVariablesCatalog(data string) (status, variables map[string]variableInfo, err error) {
+	status = map[string]variableInfo{}
+	variables = map[string]variableInfo{}
+	for i, line := range strings.Split(data, "\n") {
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Split(line, "\t")
+		if len(fields) != 4 {
+			return nil, nil, fmt.Errorf("line %d: expected 4 fields, got %d", i+1, len(fields))
+		}
+		kind, name, info := fields[0], fields[1], variableInfo{Unit: fields[2], Help: fields[3]}
+		if validPrometheusName(name) != name {
+			return nil, nil, fmt.Errorf("line %d: invalid name %q", i+1, name)
+		}
+		switch kind {
+		case "status":
+			status[name] = info
+		case "variable":
+			variables[name] = info
+		default:
+			return nil, nil, fmt.Errorf("line %d: unknown kind %q", i+1, kind)
+		}
+	}
+	return status, variables, nil
+}
+
+func mustParseVariablesCatalog(data string) (status, variables map[string]variableInfo) {
+	status, variables, err := parseVariablesCatalog(data)
+	if err != nil {
+		panic(fmt.Sprintf("invalid variables catalog: %s", err))
+	}
+	return status, variables
+}
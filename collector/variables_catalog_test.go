@@ -0,0 +1,57 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collector
+
+import (
+	"testing"
+)
+
+func TestParseVariablesCatalog(t *testing.T) {
+	status, variables, err := parseVariablesCatalog("# comment\n\nstatus\tuptime\tseconds\tServer uptime.\nvariable\tmax_connections\t\tMax connections.\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := status["uptime"].help("generic"), "Server uptime. Unit: seconds."; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got, want := variables["max_connections"].help("generic"), "Max connections."; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got, want := variables["unknown"].help("generic"), "generic"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	for _, data := range []string{
+		"status\tuptime\tseconds\n",
+		"other\tuptime\t\tServer uptime.\n",
+		"status\tUptime\t\tServer uptime.\n",
+	} {
+		if _, _, err := parseVariablesCatalog(data); err == nil {
+			t.Errorf("expected error parsing %q", data)
+		}
+	}
+}
+
+func TestVariablesCatalogCoversStatusTypes(t *testing.T) {
+	for name := range globalStatusTypes {
+		if globalStatusCatalog[name].Help == "" {
+			t.Errorf("no help text for status variable %q", name)
+		}
+	}
+	for _, name := range []string{"max_connections", "innodb_buffer_pool_size", "rocksdb_block_cache_size"} {
+		if globalVariablesCatalog[name].Help == "" {
+			t.Errorf("no help text for system variable %q", name)
+		}
+	}
+}
@@ -0,0 +1,186 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/alecthomas/kingpin/v2"
+
+	"github.com/prometheus/mysqld_exporter/collector"
+)
+
+// Tunable flags of the collectors.
+var (
+	collectHeartbeatDatabase = kingpin.Flag(
+		"collect.heartbeat.database",
+		"Database from where to collect heartbeat data",
+	).Default("heartbeat").String()
+	collectHeartbeatTable = kingpin.Flag(
+		"collect.heartbeat.table",
+		"Table from where to collect heartbeat data",
+	).Default("heartbeat").String()
+	collectHeartbeatUtc = kingpin.Flag(
+		"collect.heartbeat.utc",
+		"Use UTC for timestamps of the current server (`pt-heartbeat` is called with `--utc`)",
+	).Bool()
+
+	innodbTrxTopN = kingpin.Flag(
+		"collect.info_schema.innodb_trx.top_n",
+		"Number of oldest InnoDB transactions to expose with their user and host, 0 to disable",
+	).Default("10").Int()
+
+	processlistMinTime = kingpin.Flag(
+		"collect.info_schema.processlist.min_time",
+		"Minimum time a thread must be in each state to be counted",
+	).Default("0").Int()
+	processesByUser = kingpin.Flag(
+		"collect.info_schema.processlist.processes_by_user",
+		"Enable collecting the number of processes by user",
+	).Default("true").Bool()
+	processesByHost = kingpin.Flag(
+		"collect.info_schema.processlist.processes_by_host",
+		"Enable collecting the number of processes by host",
+	).Default("true").Bool()
+
+	tableSchemaDatabases = kingpin.Flag(
+		"collect.info_schema.tables.databases",
+		"The list of databases to collect table stats for, or '*' for all",
+	).Default("*").String()
+
+	userPrivileges = kingpin.Flag(
+		"collect.mysql.user.privileges",
+		"Enable collecting user privileges from mysql.user",
+	).Default("false").Bool()
+
+	nativeHistograms = kingpin.Flag(
+		"collect.native_histograms",
+		"Expose latency distributions from information_schema.query_response_time and performance_schema digests as native histograms. Requires scraping with the protobuf format.",
+	).Default("false").Bool()
+	nativeHistogramSchema = kingpin.Flag(
+		"collect.native_histograms.schema",
+		"Resolution of the native histograms, from -4 to 8. Bucket boundaries grow by a factor of 2^(2^-schema).",
+	).Default("3").Int32()
+
+	perfEventsStatementsLimit = kingpin.Flag(
+		"collect.perf_schema.eventsstatements.limit",
+		"Limit the number of events statements digests by response time",
+	).Default("250").Int()
+	perfEventsStatementsTimeLimit = kingpin.Flag(
+		"collect.perf_schema.eventsstatements.timelimit",
+		"Limit how old the 'last_seen' events statements can be, in seconds",
+	).Default("86400").Int()
+	perfEventsStatementsDigestTextLimit = kingpin.Flag(
+		"collect.perf_schema.eventsstatements.digest_text_limit",
+		"Maximum length of the normalized statement text",
+	).Default("120").Int()
+	perfEventsStatementsExcludeSchemas = kingpin.Flag(
+		"collect.perf_schema.eventsstatements.exclude_schemas",
+		"Additional schema name to exclude (always excludes mysql, performance_schema, information_schema). Repeatable",
+	).Default("").Strings()
+	excludeExporterStatements = kingpin.Flag(
+		"collect.perf_schema.exclude_exporter_statements",
+		"Exclude the exporter's own statements from the statement digest metrics, by the comment of their sample query text. Requires MySQL 8.0.3 or later and --exporter.query_comments.",
+	).Default("false").Bool()
+
+	perfFileInstancesFilter = kingpin.Flag(
+		"collect.perf_schema.file_instances.filter",
+		"RegEx file_name filter for performance_schema.file_summary_by_instance",
+	).Default(".*").String()
+	perfFileInstancesRemovePrefix = kingpin.Flag(
+		"collect.perf_schema.file_instances.remove_prefix",
+		"Remove path prefix in performance_schema.file_summary_by_instance",
+	).Default("/var/lib/mysql/").String()
+
+	perfMemoryEventsRemovePrefix = kingpin.Flag(
+		"collect.perf_schema.memory_events.remove_prefix",
+		"Remove instrument prefix in performance_schema.memory_summary_global_by_event_name",
+	).Default("memory/").String()
+)
+
+// checkCollectorFlags validates the tunable flags kingpin cannot check.
+func checkCollectorFlags() error {
+	if *nativeHistogramSchema < -4 || *nativeHistogramSchema > 8 {
+		return fmt.Errorf("--collect.native_histograms.schema must be between -4 and 8, got %d", *nativeHistogramSchema)
+	}
+	return nil
+}
+
+// configureScraper returns the scraper with the options set by the flags.
+// Scrapers without options are returned as is.
+func configureScraper(scraper collector.Scraper) collector.Scraper {
+	histograms := collector.NativeHistogramOptions{
+		Enabled: *nativeHistograms,
+		Schema:  *nativeHistogramSchema,
+	}
+	switch scraper.(type) {
+	case collector.ScrapeHeartbeat:
+		return collector.ScrapeHeartbeat{Options: &collector.HeartbeatOptions{
+			Database: *collectHeartbeatDatabase,
+			Table:    *collectHeartbeatTable,
+			UTC:      *collectHeartbeatUtc,
+		}}
+	case collector.ScrapeInnodbTrx:
+		return collector.ScrapeInnodbTrx{Options: &collector.InnodbTrxOptions{TopN: *innodbTrxTopN}}
+	case collector.ScrapeProcesslist:
+		return collector.ScrapeProcesslist{Options: &collector.ProcesslistOptions{
+			MinTime:         *processlistMinTime,
+			ProcessesByUser: *processesByUser,
+			ProcessesByHost: *processesByHost,
+		}}
+	case collector.ScrapeTableSchema:
+		var databases []string
+		if *tableSchemaDatabases != "*" {
+			databases = strings.Split(*tableSchemaDatabases, ",")
+		}
+		return collector.ScrapeTableSchema{Options: &collector.TableSchemaOptions{Databases: databases}}
+	case collector.ScrapeUser:
+		return collector.ScrapeUser{Options: &collector.UserOptions{Privileges: *userPrivileges}}
+	case collector.ScrapeQueryResponseTime:
+		return collector.ScrapeQueryResponseTime{Options: &collector.QueryResponseTimeOptions{NativeHistograms: histograms}}
+	case collector.ScrapePerfEventsStatements:
+		return collector.ScrapePerfEventsStatements{Options: &collector.PerfEventsStatementsOptions{
+			Limit:                     *perfEventsStatementsLimit,
+			TimeLimit:                 *perfEventsStatementsTimeLimit,
+			DigestTextLimit:           *perfEventsStatementsDigestTextLimit,
+			ExcludeSchemas:            *perfEventsStatementsExcludeSchemas,
+			ExcludeExporterStatements: *excludeExporterStatements,
+			NativeHistograms:          histograms,
+		}}
+	case collector.ScrapePerfEventsStatementsSum:
+		return collector.ScrapePerfEventsStatementsSum{Options: &collector.PerfEventsStatementsSumOptions{
+			ExcludeExporterStatements: *excludeExporterStatements,
+		}}
+	case collector.ScrapePerfFileInstances:
+		return collector.ScrapePerfFileInstances{Options: &collector.PerfFileInstancesOptions{
+			Filter:       *perfFileInstancesFilter,
+			RemovePrefix: *perfFileInstancesRemovePrefix,
+		}}
+	case collector.ScrapePerfMemoryEvents:
+		return collector.ScrapePerfMemoryEvents{Options: &collector.PerfMemoryEventsOptions{
+			RemovePrefix: *perfMemoryEventsRemovePrefix,
+		}}
+	}
+	return scraper
+}
+
+// configureScrapers applies configureScraper to each scraper.
+func configureScrapers(scrapers []collector.Scraper) []collector.Scraper {
+	configured := make([]collector.Scraper, 0, len(scrapers))
+	for _, scraper := range scrapers {
+		configured = append(configured, configureScraper(scraper))
+	}
+	return configured
+}
@@ -0,0 +1,68 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/prometheus/mysqld_exporter/collector"
+)
+
+func TestConfigureScrapers(t *testing.T) {
+	defer func(v string) { *tableSchemaDatabases = v }(*tableSchemaDatabases)
+	defer func(v string) { *collectHeartbeatTable = v }(*collectHeartbeatTable)
+	defer func(v int) { *innodbTrxTopN = v }(*innodbTrxTopN)
+	*tableSchemaDatabases = "db1,db2"
+	*collectHeartbeatTable = "pt_heartbeat"
+	*innodbTrxTopN = 5
+
+	scrapers := configureScrapers([]collector.Scraper{
+		collector.ScrapeGlobalStatus{},
+		collector.ScrapeTableSchema{},
+		collector.ScrapeHeartbeat{},
+		collector.ScrapeInnodbTrx{},
+	})
+
+	if _, ok := scrapers[0].(collector.ScrapeGlobalStatus); !ok {
+		t.Errorf("unexpected scraper %#v", scrapers[0])
+	}
+	tables := scrapers[1].(collector.ScrapeTableSchema)
+	if tables.Options == nil || !slices.Equal(tables.Options.Databases, []string{"db1", "db2"}) {
+		t.Errorf("unexpected table schema options %+v", tables.Options)
+	}
+	heartbeat := scrapers[2].(collector.ScrapeHeartbeat)
+	if heartbeat.Options == nil || heartbeat.Options.Table != "pt_heartbeat" {
+		t.Errorf("unexpected heartbeat options %+v", heartbeat.Options)
+	}
+
+	if trx := scrapers[3].(collector.ScrapeInnodbTrx); trx.Options == nil || trx.Options.TopN != 5 {
+		t.Errorf("unexpected innodb_trx options %+v", trx.Options)
+	}
+
+	*tableSchemaDatabases = "*"
+	if tables := configureScraper(collector.ScrapeTableSchema{}).(collector.ScrapeTableSchema); tables.Options.Databases != nil {
+		t.Errorf("'*' should select all databases, got %v", tables.Options.Databases)
+	}
+}
+
+func TestCheckCollectorFlags(t *testing.T) {
+	defer func(v int32) { *nativeHistogramSchema = v }(*nativeHistogramSchema)
+	for schema, valid := range map[int32]bool{-5: false, -4: true, 3: true, 8: true, 9: false} {
+		*nativeHistogramSchema = schema
+		if err := checkCollectorFlags(); (err == nil) != valid {
+			t.Errorf("schema %d: got error %v", schema, err)
+		}
+	}
+}
@@ -59,6 +59,8 @@ var (
 
 type Config struct {
 	Sections map[string]MySqlConfig
+	// SectionErrors holds the sections skipped because they failed to parse or validate.
+	SectionErrors map[string]error
 }
 
 type MySqlConfig struct {
@@ -87,7 +89,7 @@ func (ch *MySqlConfigHandler) GetConfig() *Config {
 	return ch.Config
 }
 
-func (ch *MySqlConfigHandler) ReloadConfig(filename string, mysqldAddress string, mysqldUser string, tlsInsecureSkipVerify bool, logger *slog.Logger) error {
+func (ch *MySqlConfigHandler) ReloadConfig(filename string, mysqldAddress string, mysqldUser string, tlsInsecureSkipVerify bool, logger *slog.Logger) (err error) {
 	var host, port string
 	defer func() {
 		if err != nil {
@@ -132,7 +134,7 @@ func (ch *MySqlConfigHandler) ReloadConfig(filename string, mysqldAddress string
 	}
 
 	cfg.ValueMapper = os.ExpandEnv
-	config := &Config{}
+	config := &Config{SectionErrors: make(map[string]error)}
 	m := make(map[string]MySqlConfig)
 	for _, sec := range cfg.Sections() {
 		sectionName := sec.Name()
@@ -148,10 +150,12 @@ func (ch *MySqlConfigHandler) ReloadConfig(filename string, mysqldAddress string
 		err = sec.StrictMapTo(mysqlcfg)
 		if err != nil {
 			logger.Error("failed to parse config", "section", sectionName, "err", err)
+			config.SectionErrors[sectionName] = err
 			continue
 		}
 		if err := mysqlcfg.validateConfig(); err != nil {
 			logger.Error("failed to validate config", "section", sectionName, "err", err)
+			config.SectionErrors[sectionName] = err
 			continue
 		}
 
@@ -18,10 +18,27 @@ import (
 	"os"
 	"testing"
 
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/prometheus/common/promslog"
 	"github.com/smartystreets/goconvey/convey"
 )
 
+func TestReloadSuccessMetric(t *testing.T) {
+	c := MySqlConfigHandler{Config: &Config{}}
+	if err := c.ReloadConfig("testdata/missing.cnf", "localhost:3306", "", true, promslog.NewNopLogger()); err == nil {
+		t.Fatal("reloading a missing file should fail")
+	}
+	if got := testutil.ToFloat64(configReloadSuccess); got != 0 {
+		t.Errorf("got %v after a failed reload, want 0", got)
+	}
+	if err := c.ReloadConfig("testdata/client.cnf", "localhost:3306", "", true, promslog.NewNopLogger()); err != nil {
+		t.Fatal(err)
+	}
+	if got := testutil.ToFloat64(configReloadSuccess); got != 1 {
+		t.Errorf("got %v after a successful reload, want 1", got)
+	}
+}
+
 func TestValidateConfig(t *testing.T) {
 	convey.Convey("Working config validation", t, func() {
 		c := MySqlConfigHandler{
@@ -140,6 +157,21 @@ func TestValidateConfig(t *testing.T) {
 		)
 	})
 
+	convey.Convey("Invalid sections are reported", t, func() {
+		c := MySqlConfigHandler{
+			Config: &Config{},
+		}
+		os.Clearenv()
+		if err := c.ReloadConfig("testdata/invalid_section.cnf", "localhost:3306", "", true, promslog.NewNopLogger()); err != nil {
+			t.Error(err)
+		}
+
+		cfg := c.GetConfig()
+		convey.So(cfg.Sections, convey.ShouldContainKey, "client")
+		convey.So(cfg.Sections, convey.ShouldNotContainKey, "other")
+		convey.So(cfg.SectionErrors, convey.ShouldContainKey, "other")
+	})
+
 	convey.Convey("Client without password", t, func() {
 		c := MySqlConfigHandler{
 			Config: &Config{},
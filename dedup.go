@@ -0,0 +1,173 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alecthomas/kingpin/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/prometheus/mysqld_exporter/collector"
+)
+
+var (
+	scrapeDedup = kingpin.Flag(
+		"exporter.deduplicate_scrapes",
+		"Share a single in-flight scrape between concurrent requests for the same target, module and collectors.",
+	).Default("false").Bool()
+	scrapeCacheTTL = kingpin.Flag(
+		"exporter.scrape_cache_ttl",
+		"Reuse the result of a deduplicated scrape for this long after it finished. Requires --exporter.deduplicate_scrapes.",
+	).Default("0s").Duration()
+
+	scrapesDeduplicated = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "mysql",
+		Subsystem: "exporter",
+		Name:      "scrapes_deduplicated_total",
+		Help:      "Total number of scrape requests served from a shared or cached scrape.",
+	})
+
+	scrapes = newScrapeGroup()
+)
+
+func init() {
+	prometheus.MustRegister(scrapesDeduplicated)
+}
+
+// scrapeCall is an in-flight or recently finished scrape.
+type scrapeCall struct {
+	done     chan struct{}
+	finished time.Time
+	mfs      []*dto.MetricFamily
+	err      error
+}
+
+// scrapeGroup deduplicates scrapes with the same key. Callers arriving while
+// a scrape is in flight wait for its result instead of starting their own.
+type scrapeGroup struct {
+	mu    sync.Mutex
+	calls map[string]*scrapeCall
+}
+
+func newScrapeGroup() *scrapeGroup {
+	return &scrapeGroup{
+		calls: make(map[string]*scrapeCall),
+	}
+}
+
+// do runs gather once for all concurrent callers with the same key. A
+// finished result is reused for ttl. The returned bool reports whether the
+// result was shared with another caller.
+func (g *scrapeGroup) do(ctx context.Context, key string, ttl time.Duration, gather func(context.Context) ([]*dto.MetricFamily, error)) ([]*dto.MetricFamily, bool, error) {
+	g.mu.Lock()
+	if call, ok := g.calls[key]; ok {
+		select {
+		case <-call.done:
+			if time.Since(call.finished) < ttl {
+				g.mu.Unlock()
+				return call.mfs, true, call.err
+			}
+		default:
+			g.mu.Unlock()
+			select {
+			case <-call.done:
+				return call.mfs, true, call.err
+			case <-ctx.Done():
+				return nil, true, ctx.Err()
+			}
+		}
+	}
+	call := &scrapeCall{done: make(chan struct{})}
+	g.calls[key] = call
+	g.mu.Unlock()
+
+	// Detach from the caller's cancellation so that a disconnecting client
+	// does not fail the scrape for everyone waiting on it, but keep its
+	// deadline.
+	scrapeCtx := context.WithoutCancel(ctx)
+	if deadline, ok := ctx.Deadline(); ok {
+		var cancel context.CancelFunc
+		scrapeCtx, cancel = context.WithDeadline(scrapeCtx, deadline)
+		defer cancel()
+	}
+	call.mfs, call.err = gather(scrapeCtx)
+
+	g.mu.Lock()
+	call.finished = time.Now()
+	close(call.done)
+	g.mu.Unlock()
+	time.AfterFunc(max(ttl, 0), func() {
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		if g.calls[key] == call {
+			delete(g.calls, key)
+		}
+	})
+
+	return call.mfs, false, call.err
+}
+
+// reset forgets all scrapes, so that callers arriving afterwards start a new
+// scrape, e.g. with reloaded credentials. Callers already waiting for an
+// in-flight scrape still get its result.
+func (g *scrapeGroup) reset() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	clear(g.calls)
+}
+
+// scrapeKey identifies scrapes that may share a result.
+func scrapeKey(target, authModule string, scrapers []collector.Scraper) string {
+	names := make([]string, 0, len(scrapers))
+	for _, scraper := range scrapers {
+		names = append(names, scraper.Name())
+	}
+	slices.Sort(names)
+	return target + "\x00" + authModule + "\x00" + strings.Join(names, ",")
+}
+
+// newTargetGatherer returns a Gatherer that scrapes dsn with the given
+// scrapers and the tunables of the profile, if any, deduplicating concurrent
+// scrapes of the same target when enabled.
+func newTargetGatherer(ctx context.Context, target, authModule, dsn string, scrapers []collector.Scraper, logger *slog.Logger, profile *scrapeProfile) prometheus.Gatherer {
+	opts := exporterOpts(authModule, profile)
+	key := scrapeKey(target, authModule, scrapers)
+	if profile != nil {
+		key += "\x00" + profile.Name
+	}
+	gather := func(ctx context.Context) ([]*dto.MetricFamily, error) {
+		registry := prometheus.NewRegistry()
+		registry.MustRegister(collector.New(ctx, dsn, scrapers, logger, opts...))
+		return registry.Gather()
+	}
+	if !*scrapeDedup {
+		return prometheus.GathererFunc(func() ([]*dto.MetricFamily, error) {
+			return gather(ctx)
+		})
+	}
+	return prometheus.GathererFunc(func() ([]*dto.MetricFamily, error) {
+		mfs, shared, err := scrapes.do(ctx, key, *scrapeCacheTTL, gather)
+		if shared {
+			scrapesDeduplicated.Inc()
+		}
+		return mfs, err
+	})
+}
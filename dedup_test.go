@@ -0,0 +1,119 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/prometheus/mysqld_exporter/collector"
+)
+
+func TestScrapeGroupSharesInFlightScrape(t *testing.T) {
+	g := newScrapeGroup()
+	var calls atomic.Int32
+	release := make(chan struct{})
+	gather := func(context.Context) ([]*dto.MetricFamily, error) {
+		calls.Add(1)
+		<-release
+		return []*dto.MetricFamily{{}}, nil
+	}
+
+	var wg sync.WaitGroup
+	var sharedCount atomic.Int32
+	for range 5 {
+		wg.Go(func() {
+			mfs, shared, err := g.do(context.Background(), "key", 0, gather)
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if len(mfs) != 1 {
+				t.Errorf("expected 1 metric family, got %d", len(mfs))
+			}
+			if shared {
+				sharedCount.Add(1)
+			}
+		})
+	}
+	// Wait until the first scrape is in flight and the others queued behind it.
+	for calls.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if got := calls.Load(); got != 1 {
+		t.Fatalf("expected 1 scrape, got %d", got)
+	}
+	if got := sharedCount.Load(); got != 4 {
+		t.Fatalf("expected 4 shared results, got %d", got)
+	}
+}
+
+func TestScrapeGroupTTL(t *testing.T) {
+	g := newScrapeGroup()
+	var calls atomic.Int32
+	gather := func(context.Context) ([]*dto.MetricFamily, error) {
+		calls.Add(1)
+		return nil, nil
+	}
+
+	if _, shared, _ := g.do(context.Background(), "key", time.Hour, gather); shared {
+		t.Fatal("first scrape should not be shared")
+	}
+	if _, shared, _ := g.do(context.Background(), "key", time.Hour, gather); !shared {
+		t.Fatal("second scrape within ttl should be shared")
+	}
+	if _, shared, _ := g.do(context.Background(), "other", time.Hour, gather); shared {
+		t.Fatal("scrape with a different key should not be shared")
+	}
+	if got := calls.Load(); got != 2 {
+		t.Fatalf("expected 2 scrapes, got %d", got)
+	}
+
+	// A config reload discards cached results.
+	g.reset()
+	if _, shared, _ := g.do(context.Background(), "key", time.Hour, gather); shared {
+		t.Fatal("scrape after a reset should not be shared")
+	}
+
+	// Without a ttl, finished scrapes are never reused.
+	g = newScrapeGroup()
+	calls.Store(0)
+	for range 3 {
+		if _, shared, _ := g.do(context.Background(), "key", 0, gather); shared {
+			t.Fatal("sequential scrapes without ttl should not be shared")
+		}
+	}
+	if got := calls.Load(); got != 3 {
+		t.Fatalf("expected 3 scrapes, got %d", got)
+	}
+}
+
+func TestScrapeKey(t *testing.T) {
+	a := scrapeKey("db:3306", "client", []collector.Scraper{collector.ScrapeGlobalStatus{}, collector.ScrapeSlaveStatus{}})
+	b := scrapeKey("db:3306", "client", []collector.Scraper{collector.ScrapeSlaveStatus{}, collector.ScrapeGlobalStatus{}})
+	if a != b {
+		t.Errorf("scrape key should not depend on collector order: %q != %q", a, b)
+	}
+	if c := scrapeKey("db:3306", "client.other", []collector.Scraper{collector.ScrapeGlobalStatus{}, collector.ScrapeSlaveStatus{}}); a == c {
+		t.Errorf("scrape key should depend on the auth module")
+	}
+}
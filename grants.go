@@ -0,0 +1,50 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/alecthomas/kingpin/v2"
+
+	"github.com/prometheus/mysqld_exporter/collector"
+)
+
+var (
+	grantsCmd = kingpin.Command(
+		"grants",
+		"Print the CREATE USER and GRANT statements needed by the enabled collectors.",
+	)
+	grantsUser = grantsCmd.Flag(
+		"grants.user",
+		"User name to grant privileges to.",
+	).Default("exporter").String()
+	grantsHost = grantsCmd.Flag(
+		"grants.host",
+		"Host the user connects from.",
+	).Default("localhost").String()
+	grantsPassword = grantsCmd.Flag(
+		"grants.password",
+		"Password to set in the CREATE USER statement.",
+	).Default("XXXXXXXX").String()
+)
+
+// printGrants writes the statements creating a user with the privileges
+// required by the scrapers.
+func printGrants(w io.Writer, scrapers []collector.Scraper) {
+	for _, statement := range collector.GrantStatements(*grantsUser, *grantsHost, *grantsPassword, scrapers) {
+		fmt.Fprintln(w, statement)
+	}
+}
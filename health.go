@@ -0,0 +1,176 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/alecthomas/kingpin/v2"
+	_ "github.com/go-sql-driver/mysql"
+)
+
+var (
+	readyTimeout = kingpin.Flag(
+		"exporter.ready_timeout",
+		"Timeout for pinging the default target in the readiness check.",
+	).Default("2s").Duration()
+	readyRequiresDatabase = kingpin.Flag(
+		"exporter.ready_requires_database",
+		"Report the exporter as not ready while the default target cannot be pinged.",
+	).Default("false").Bool()
+	readyCacheTTL = kingpin.Flag(
+		"exporter.ready_cache_ttl",
+		"Reuse the result of pinging the default target in the readiness check for this long.",
+	).Default("5s").Duration()
+
+	configState configStatus
+	readyPing   pingCache
+)
+
+// configStatus remembers the result of the last configuration (re)load.
+type configStatus struct {
+	mu  sync.RWMutex
+	err error
+}
+
+func (s *configStatus) set(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.err = err
+}
+
+func (s *configStatus) get() error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.err
+}
+
+// pingCache remembers the result of the last ping of the default target, so
+// that frequent readiness probes do not open a connection each.
+type pingCache struct {
+	mu  sync.Mutex
+	at  time.Time
+	err error
+}
+
+// ping returns the result of the last ping if it is younger than ttl, and
+// pings the default target otherwise.
+func (p *pingCache) ping(ctx context.Context, ttl time.Duration) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.at.IsZero() && time.Since(p.at) < ttl {
+		return p.err
+	}
+	// A client giving up must not leave a failure for the next probes.
+	p.err = pingDefaultTarget(context.WithoutCancel(ctx))
+	p.at = time.Now()
+	return p.err
+}
+
+// reset forgets the last ping.
+func (p *pingCache) reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.at = time.Time{}
+}
+
+// reloadConfig reloads the configuration and records the outcome for the
+// health checks.
+func reloadConfig(logger *slog.Logger) error {
+	// Results of scrapes and pings with the previous configuration must not
+	// be reused.
+	defer scrapes.reset()
+	defer readyPing.reset()
+	if replaySnapshot != nil {
+		c.Lock()
+		c.Config = replayConfig(replaySnapshot)
+		c.Unlock()
+		configState.set(nil)
+		return nil
+	}
+	err := c.ReloadConfig(*configMycnf, *mysqldAddress, *mysqldUser, *tlsInsecureSkipVerify, logger)
+	configState.set(err)
+	return err
+}
+
+// checkConfig returns an error if no valid configuration is loaded.
+func checkConfig() error {
+	if err := configState.get(); err != nil {
+		return fmt.Errorf("last configuration reload failed: %w", err)
+	}
+	cfg := c.GetConfig()
+	if cfg == nil || len(cfg.Sections) == 0 {
+		return errors.New("no configuration loaded")
+	}
+	return nil
+}
+
+// pingDefaultTarget checks that the target of the [client] section responds.
+func pingDefaultTarget(ctx context.Context) error {
+	cfgsection, ok := c.GetConfig().Sections["client"]
+	if !ok {
+		return errors.New("no [client] section in config")
+	}
+	dsn, err := cfgsection.FormDSN("")
+	if err != nil {
+		return fmt.Errorf("failed to form dsn from section [client]: %w", err)
+	}
+	db, err := sql.Open("mysql", dsn)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	ctx, cancel := context.WithTimeout(ctx, *readyTimeout)
+	defer cancel()
+	return db.PingContext(ctx)
+}
+
+// handleHealthy reports whether the process is alive and has a configuration.
+func handleHealthy(w http.ResponseWriter, r *http.Request) {
+	cfg := c.GetConfig()
+	if cfg == nil || len(cfg.Sections) == 0 {
+		http.Error(w, "no configuration loaded", http.StatusServiceUnavailable)
+		return
+	}
+	_, _ = w.Write([]byte(`ok`))
+}
+
+// handleReady reports whether the configuration is valid and, if required,
+// whether the default target can be reached.
+func handleReady(logger *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := checkConfig(); err != nil {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		if err := readyPing.ping(r.Context(), *readyCacheTTL); err != nil {
+			if *readyRequiresDatabase {
+				logger.Debug("Readiness check failed", "err", err)
+				http.Error(w, fmt.Sprintf("error pinging mysqld: %s", err), http.StatusServiceUnavailable)
+				return
+			}
+			_, _ = fmt.Fprintf(w, "ok (error pinging mysqld: %s)", err)
+			return
+		}
+		_, _ = w.Write([]byte(`ok`))
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/promslog"
+
+	"github.com/prometheus/mysqld_exporter/config"
+)
+
+func TestHandleReady(t *testing.T) {
+	c.Config = &config.Config{Sections: map[string]config.MySqlConfig{
+		"client": {User: "foo", Host: "127.0.0.1", Port: 1},
+	}}
+	configState.set(nil)
+	timeout := time.Second
+	readyTimeout = &timeout
+	requiresDatabase := false
+	readyRequiresDatabase = &requiresDatabase
+	defer func() {
+		c.Config = &config.Config{}
+		configState.set(nil)
+	}()
+
+	handler := handleReady(promslog.NewNopLogger())
+	ready := func() int {
+		rec := httptest.NewRecorder()
+		handler(rec, httptest.NewRequest("GET", "/-/ready", nil))
+		return rec.Code
+	}
+
+	if got := ready(); got != http.StatusOK {
+		t.Errorf("unreachable database without ready_requires_database: got %d, want %d", got, http.StatusOK)
+	}
+
+	requiresDatabase = true
+	if got := ready(); got != http.StatusServiceUnavailable {
+		t.Errorf("unreachable database with ready_requires_database: got %d, want %d", got, http.StatusServiceUnavailable)
+	}
+
+	requiresDatabase = false
+	configState.set(errors.New("bad config"))
+	if got := ready(); got != http.StatusServiceUnavailable {
+		t.Errorf("failed config reload: got %d, want %d", got, http.StatusServiceUnavailable)
+	}
+}
+
+func TestReadyRecoversAfterReload(t *testing.T) {
+	cnf := filepath.Join(t.TempDir(), "my.cnf")
+	configMycnf = &cnf
+	address := "localhost:3306"
+	mysqldAddress = &address
+	ttl := time.Hour
+	readyCacheTTL = &ttl
+	requiresDatabase := false
+	readyRequiresDatabase = &requiresDatabase
+	defer func() {
+		c.Config = &config.Config{}
+		configState.set(nil)
+		readyPing.reset()
+	}()
+
+	handler := handleReady(promslog.NewNopLogger())
+	ready := func() int {
+		rec := httptest.NewRecorder()
+		handler(rec, httptest.NewRequest("GET", "/-/ready", nil))
+		return rec.Code
+	}
+
+	if err := os.WriteFile(cnf, []byte("[client]\nhost = 127.0.0.1\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := reloadConfig(promslog.NewNopLogger()); err == nil {
+		t.Fatal("reloading a config without user should fail")
+	}
+	if got := ready(); got != http.StatusServiceUnavailable {
+		t.Errorf("failed config reload: got %d, want %d", got, http.StatusServiceUnavailable)
+	}
+
+	if err := os.WriteFile(cnf, []byte("[client]\nuser = foo\nhost = 127.0.0.1\nport = 1\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := reloadConfig(promslog.NewNopLogger()); err != nil {
+		t.Fatal(err)
+	}
+	if got := ready(); got != http.StatusOK {
+		t.Errorf("successful config reload: got %d, want %d", got, http.StatusOK)
+	}
+
+	// The failed ping is cached until the next reload.
+	requiresDatabase = true
+	if got := ready(); got != http.StatusServiceUnavailable {
+		t.Errorf("unreachable database: got %d, want %d", got, http.StatusServiceUnavailable)
+	}
+	if readyPing.at.IsZero() || readyPing.err == nil {
+		t.Error("failed ping was not cached")
+	}
+	if err := reloadConfig(promslog.NewNopLogger()); err != nil {
+		t.Fatal(err)
+	}
+	if !readyPing.at.IsZero() {
+		t.Error("reload did not discard the cached ping")
+	}
+}
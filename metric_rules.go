@@ -0,0 +1,137 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/alecthomas/kingpin/v2"
+	"github.com/prometheus/common/model"
+
+	"github.com/prometheus/mysqld_exporter/collector"
+)
+
+var (
+	metricKeep = kingpin.Flag(
+		"exporter.metric_keep",
+		"Only expose metrics whose name matches this anchored regular expression. Repeatable.",
+	).Strings()
+	metricDrop = kingpin.Flag(
+		"exporter.metric_drop",
+		"Drop metrics whose name matches this anchored regular expression. Repeatable.",
+	).Strings()
+	labelDrop = kingpin.Flag(
+		"exporter.label_drop",
+		"Remove the label with this name from all metrics. Repeatable.",
+	).Strings()
+	labelRename = kingpin.Flag(
+		"exporter.label_rename",
+		"Rename a label on all metrics, e.g. schema=database. Repeatable.",
+	).Strings()
+	labelHashLength = kingpin.Flag(
+		"exporter.label_hash_length",
+		"Replace label values longer than this many bytes by a hash of the value. 0 disables hashing.",
+	).Default("0").Int()
+	privacyHash = kingpin.Flag(
+		"exporter.privacy.hash",
+		"Replace the values of a class of sensitive labels by a stable hash: user, host, schema, table or digest_text. Repeatable.",
+	).Strings()
+	privacyRedact = kingpin.Flag(
+		"exporter.privacy.redact",
+		"Replace the values of a class of sensitive labels by \"redacted\": user, host, schema, table or digest_text. Repeatable.",
+	).Strings()
+	privacySaltFile = kingpin.Flag(
+		"exporter.privacy.salt_file",
+		"Path to a file containing a secret hashed with the label values of --exporter.privacy.hash.",
+	).String()
+
+	metricRules collector.MetricRules
+)
+
+// compileAnchored compiles the alternation of the patterns, anchored at both
+// ends like Prometheus relabeling. It returns nil without patterns.
+func compileAnchored(patterns []string) (*regexp.Regexp, error) {
+	if len(patterns) == 0 {
+		return nil, nil
+	}
+	return regexp.Compile("^(?:" + strings.Join(patterns, "|") + ")$")
+}
+
+// parseMetricRules builds the metric rules from the flag values.
+func parseMetricRules(keep, drop, dropLabels, renameLabels []string, hashLength int) (collector.MetricRules, error) {
+	var (
+		rules = collector.MetricRules{HashLength: hashLength}
+		err   error
+	)
+	if rules.Keep, err = compileAnchored(keep); err != nil {
+		return rules, fmt.Errorf("invalid metric keep pattern: %w", err)
+	}
+	if rules.Drop, err = compileAnchored(drop); err != nil {
+		return rules, fmt.Errorf("invalid metric drop pattern: %w", err)
+	}
+	rules.DropLabels = dropLabels
+	for _, rename := range renameLabels {
+		from, to, ok := strings.Cut(rename, "=")
+		if !ok || !model.LegacyValidation.IsValidLabelName(from) || !model.LegacyValidation.IsValidLabelName(to) {
+			return rules, fmt.Errorf("invalid label rename %q, expected old=new with valid label names", rename)
+		}
+		if rules.RenameLabels == nil {
+			rules.RenameLabels = map[string]string{}
+		}
+		if _, ok := rules.RenameLabels[from]; ok {
+			return rules, fmt.Errorf("label %q is renamed twice", from)
+		}
+		for other, target := range rules.RenameLabels {
+			if target == to {
+				return rules, fmt.Errorf("labels %q and %q are both renamed to %q", other, from, to)
+			}
+		}
+		rules.RenameLabels[from] = to
+	}
+	if hashLength < 0 {
+		return rules, fmt.Errorf("invalid label hash length %d", hashLength)
+	}
+	return rules, nil
+}
+
+// parsePrivacy builds the privacy settings from the flag values.
+func parsePrivacy(hash, redact []string, saltFile string) (collector.Privacy, error) {
+	var privacy collector.Privacy
+	for mode, classes := range map[collector.PrivacyMode][]string{collector.PrivacyHash: hash, collector.PrivacyRedact: redact} {
+		for _, name := range classes {
+			class, err := collector.ParseLabelClass(name)
+			if err != nil {
+				return privacy, err
+			}
+			if other, ok := privacy.Modes[class]; ok && other != mode {
+				return privacy, fmt.Errorf("label class %q is both hashed and redacted", class)
+			}
+			if privacy.Modes == nil {
+				privacy.Modes = map[collector.LabelClass]collector.PrivacyMode{}
+			}
+			privacy.Modes[class] = mode
+		}
+	}
+	if saltFile != "" {
+		salt, err := os.ReadFile(saltFile)
+		if err != nil {
+			return privacy, err
+		}
+		privacy.Salt = strings.TrimSpace(string(salt))
+	}
+	return privacy, nil
+}
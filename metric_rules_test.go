@@ -0,0 +1,98 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/prometheus/mysqld_exporter/collector"
+)
+
+func TestParseMetricRules(t *testing.T) {
+	rules, err := parseMetricRules(
+		[]string{"mysql_up", "mysql_global_.*"},
+		[]string{"mysql_global_variables_.*"},
+		[]string{"digest_text"},
+		[]string{"schema=database"},
+		64,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, keep := range map[string]bool{"mysql_up": true, "mysql_global_status_uptime": true, "mysql_upx": false, "xmysql_up": false} {
+		if got := rules.Keep.MatchString(name); got != keep {
+			t.Errorf("keep %q: got %t, want %t", name, got, keep)
+		}
+	}
+	if !rules.Drop.MatchString("mysql_global_variables_port") {
+		t.Error("expected drop pattern to match")
+	}
+	if rules.RenameLabels["schema"] != "database" {
+		t.Errorf("unexpected renames %v", rules.RenameLabels)
+	}
+
+	empty, err := parseMetricRules(nil, nil, nil, nil, 0)
+	if err != nil || empty.Keep != nil || empty.Drop != nil {
+		t.Errorf("unexpected rules %+v, err %v", empty, err)
+	}
+
+	for _, rename := range []string{"schema", "schema=data-base", "=x"} {
+		if _, err := parseMetricRules(nil, nil, nil, []string{rename}, 0); err == nil {
+			t.Errorf("expected error parsing rename %q", rename)
+		}
+	}
+	if _, err := parseMetricRules(nil, nil, nil, []string{"user=client", "host=client"}, 0); err == nil {
+		t.Error("expected error parsing renames with the same target")
+	}
+	if _, err := parseMetricRules([]string{"("}, nil, nil, nil, 0); err == nil {
+		t.Error("expected error parsing invalid pattern")
+	}
+}
+
+func TestParsePrivacy(t *testing.T) {
+	saltFile := filepath.Join(t.TempDir(), "salt")
+	if err := os.WriteFile(saltFile, []byte("secret\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	privacy, err := parsePrivacy([]string{"user", "host"}, []string{"digest_text"}, saltFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[collector.LabelClass]collector.PrivacyMode{
+		collector.LabelClassUser:       collector.PrivacyHash,
+		collector.LabelClassHost:       collector.PrivacyHash,
+		collector.LabelClassDigestText: collector.PrivacyRedact,
+	}
+	if len(privacy.Modes) != len(want) {
+		t.Errorf("unexpected modes %v", privacy.Modes)
+	}
+	for class, mode := range want {
+		if privacy.Modes[class] != mode {
+			t.Errorf("class %s: got mode %q, want %q", class, privacy.Modes[class], mode)
+		}
+	}
+	if privacy.Salt != "secret" {
+		t.Errorf("unexpected salt %q", privacy.Salt)
+	}
+
+	if _, err := parsePrivacy([]string{"password"}, nil, ""); err == nil {
+		t.Error("expected error parsing unknown label class")
+	}
+	if _, err := parsePrivacy([]string{"user"}, []string{"user"}, ""); err == nil {
+		t.Error("expected error hashing and redacting the same class")
+	}
+}
@@ -17,15 +17,22 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"maps"
 	"net/http"
 	"os"
+	"os/signal"
+	"slices"
 	"strconv"
+	"strings"
+	"sync"
+	"syscall"
 	"time"
 
 	"github.com/alecthomas/kingpin/v2"
 	"github.com/prometheus/client_golang/prometheus"
 	versioncollector "github.com/prometheus/client_golang/prometheus/collectors/version"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	dto "github.com/prometheus/client_model/go"
 	"github.com/prometheus/common/promslog"
 	"github.com/prometheus/common/promslog/flag"
 	"github.com/prometheus/common/version"
@@ -71,12 +78,76 @@ var (
 	).Default("true").Bool()
 	slowLogFilter = kingpin.Flag(
 		"exporter.log_slow_filter",
-		"Add a log_slow_filter to avoid slow query logging of scrapes. NOTE: Not supported by Oracle MySQL.",
+		"Add a log_slow_filter to avoid slow query logging of scrapes. NOTE: Not supported by Oracle MySQL, use --exporter.session.log_slow_filter instead.",
 	).Default("false").Bool()
+	sessionReadOnly = kingpin.Flag(
+		"exporter.session.read_only",
+		"Make the transactions of the exporter's session read only.",
+	).Default("false").Bool()
+	sessionReadUncommitted = kingpin.Flag(
+		"exporter.session.read_uncommitted",
+		"Use the READ UNCOMMITTED isolation level to avoid locking on information_schema scans.",
+	).Default("false").Bool()
+	sessionInnodbLockWaitTimeout = kingpin.Flag(
+		"exporter.session.innodb_lock_wait_timeout",
+		"Set innodb_lock_wait_timeout (in seconds) on the connection. 0 keeps the server default.",
+	).Default("0").Int()
+	sessionMaxExecutionTime = kingpin.Flag(
+		"exporter.session.max_execution_time",
+		"Abort statements running longer than this, using max_execution_time on MySQL and max_statement_time on MariaDB. 0 keeps the server default.",
+	).Default("0s").Duration()
+	sessionDisableBinlog = kingpin.Flag(
+		"exporter.session.disable_binlog",
+		"Set sql_log_bin=0 on the connection. Requires SUPER or SYSTEM_VARIABLES_ADMIN.",
+	).Default("false").Bool()
+	sessionLongQueryTime = kingpin.Flag(
+		"exporter.session.long_query_time",
+		"Set long_query_time on the connection. 0 keeps the server default.",
+	).Default("0s").Duration()
+	sessionLogSlowFilter = kingpin.Flag(
+		"exporter.session.log_slow_filter",
+		"Set log_slow_filter on the connection to avoid slow query logging of scrapes. Only applied on Percona Server and MariaDB.",
+	).Default("false").Bool()
+	deadlineMargin = kingpin.Flag(
+		"exporter.deadline_margin",
+		"Stop collectors still running this long before the scrape timeout and return the metrics collected so far. Ignored if it exceeds half of the scrape timeout.",
+	).Default("0").Duration()
+	collectorBackoffInitial = kingpin.Flag(
+		"exporter.collector_backoff.initial",
+		"Suspend collectors failing with a permanent error, such as access denied, for this long. Doubles on every further failure. 0 disables suspending collectors.",
+	).Default("0").Duration()
+	collectorBackoffMax = kingpin.Flag(
+		"exporter.collector_backoff.max",
+		"Maximum suspension of a collector failing with a permanent error.",
+	).Default("1h").Duration()
+	loadSheddingVariable = kingpin.Flag(
+		"exporter.load_shedding.variable",
+		"Global status variable read before every scrape to decide whether to skip expensive collectors.",
+	).Default("Threads_running").String()
+	loadSheddingThreshold = kingpin.Flag(
+		"exporter.load_shedding.threshold",
+		"Skip expensive collectors while --exporter.load_shedding.variable is above this value. 0 disables load shedding.",
+	).Default("0").Float64()
+	checkPrivileges = kingpin.Flag(
+		"exporter.check_privileges",
+		"Compare SHOW GRANTS against the privileges required by the enabled collectors on every scrape.",
+	).Default("false").Bool()
+	queryComments = kingpin.Flag(
+		"exporter.query_comments",
+		"Prefix every query with a comment naming the exporter and the collector, e.g. /* mysqld_exporter collector=info_schema.tables */.",
+	).Default("false").Bool()
+	metricsNaming = kingpin.Flag(
+		"metrics.naming",
+		"Metric naming scheme. v2 converts values to base units and applies OpenMetrics suffixes consistently.",
+	).Default(string(collector.NamingV1)).Enum(string(collector.NamingV1), string(collector.NamingV2))
 	toolkitFlags = webflag.AddFlags(kingpin.CommandLine, ":9104")
+	_            = kingpin.Command("serve", "Run the exporter (default).").Default()
 	c            = config.MySqlConfigHandler{
 		Config: &config.Config{},
 	}
+
+	// collectorBackoff suspends failing collectors across scrapes.
+	collectorBackoff *collector.CollectorBackoff
 )
 
 // scrapers lists all possible collection methods and if they should be enabled by default.
@@ -89,6 +160,7 @@ var scrapers = map[collector.Scraper]bool{
 	collector.ScrapeTableSchema{}:                         false,
 	collector.ScrapeInfoSchemaInnodbTablespaces{}:         false,
 	collector.ScrapeInnodbMetrics{}:                       false,
+	collector.ScrapeInnodbTrx{}:                           false,
 	collector.ScrapeAutoIncrementColumns{}:                false,
 	collector.ScrapeBinlogSize{}:                          false,
 	collector.ScrapePerfTableIOWaits{}:                    false,
@@ -141,6 +213,82 @@ func filterScrapers(scrapers []collector.Scraper, collectParams []string) []coll
 	return filteredScrapers
 }
 
+// exporterOpts returns the collector options configured by flags, overridden
+// by the profile if any.
+func exporterOpts(authModule string, profile *scrapeProfile) []collector.ExporterOpt {
+	opts := []collector.ExporterOpt{
+		collector.EnableLockWaitTimeout(*enableExporterLockTimeout),
+		collector.SetLockWaitTimeout(*exporterLockTimeout),
+		collector.SetSlowLogFilter(*slowLogFilter),
+		collector.EnablePrivilegeCheck(*checkPrivileges),
+		collector.SetDeadlineMargin(*deadlineMargin),
+		collector.SetLoadShedding(collector.LoadShedding{
+			Variable:  *loadSheddingVariable,
+			Threshold: *loadSheddingThreshold,
+		}),
+		collector.SetSessionGuardrails(collector.SessionGuardrails{
+			ReadOnly:              *sessionReadOnly,
+			ReadUncommitted:       *sessionReadUncommitted,
+			InnodbLockWaitTimeout: *sessionInnodbLockWaitTimeout,
+			MaxExecutionTime:      *sessionMaxExecutionTime,
+			DisableBinlog:         *sessionDisableBinlog,
+			LongQueryTime:         *sessionLongQueryTime,
+			LogSlowFilter:         *sessionLogSlowFilter,
+		}),
+		collector.EnableQueryComments(*queryComments),
+		collector.SetMetricsNaming(collector.MetricsNaming(*metricsNaming)),
+		collector.SetSeriesLimits(seriesLimits),
+		collector.SetMetricRules(metricRules),
+		collector.SetResultHandler(func(result collector.ScrapeResult) {
+			scrapeStatus.record(authModule, profile, result)
+		}),
+	}
+	if replaySnapshot != nil {
+		opts = append(opts, collector.ReplaySnapshot(replaySnapshot))
+	}
+	if tracerProvider != nil {
+		opts = append(opts, collector.SetTracerProvider(tracerProvider))
+	}
+	if collectorBackoff != nil {
+		opts = append(opts, collector.SetCollectorBackoff(collectorBackoff))
+	}
+	if profile != nil {
+		opts = append(opts, profile.opts()...)
+	}
+	return opts
+}
+
+// gatherTarget scrapes the target of a config section once and returns the
+// metrics along with the result of the scrape.
+func gatherTarget(ctx context.Context, authModule, dsn string, scrapers []collector.Scraper, logger *slog.Logger, extraOpts ...collector.ExporterOpt) ([]*dto.MetricFamily, collector.ScrapeResult, error) {
+	var result collector.ScrapeResult
+	opts := append(exporterOpts(authModule, nil), collector.SetResultHandler(func(r collector.ScrapeResult) {
+		scrapeStatus.record(authModule, nil, r)
+		result = r
+	}))
+	opts = append(opts, extraOpts...)
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(collector.New(ctx, dsn, scrapers, logger, opts...))
+	mfs, err := registry.Gather()
+	return mfs, result, err
+}
+
+// forEachTarget calls fn concurrently for every section of the config and
+// waits for all calls to return.
+func forEachTarget(logger *slog.Logger, fn func(section, dsn string)) {
+	cfg := c.GetConfig()
+	var wg sync.WaitGroup
+	for _, section := range slices.Sorted(maps.Keys(cfg.Sections)) {
+		dsn, err := cfg.Sections[section].FormDSN("")
+		if err != nil {
+			logger.Error("Failed to form dsn from section", "section", section, "err", err)
+			continue
+		}
+		wg.Go(func() { fn(section, dsn) })
+	}
+	wg.Wait()
+}
+
 func getScrapeTimeoutSeconds(r *http.Request, offset float64) (float64, error) {
 	var timeoutSeconds float64
 	if v := r.Header.Get("X-Prometheus-Scrape-Timeout-Seconds"); v != "" {
@@ -171,7 +319,9 @@ func init() {
 	prometheus.MustRegister(versioncollector.NewCollector("mysqld_exporter"))
 }
 
-func newHandler(scrapers []collector.Scraper, logger *slog.Logger) http.HandlerFunc {
+// newHandler returns the handler serving the metrics of the [client] target.
+// A non-nil profile overrides the tunables of the flags.
+func newHandler(scrapers []collector.Scraper, logger *slog.Logger, profile *scrapeProfile) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var dsn string
 		var err error
@@ -193,7 +343,9 @@ func newHandler(scrapers []collector.Scraper, logger *slog.Logger) http.HandlerF
 		collect := q["collect[]"]
 
 		// Use request context for cancellation when connection gets closed.
-		ctx := r.Context()
+		ctx, span := startScrapeSpan(r, target, "client")
+		defer span.End()
+
 		// If a timeout is configured via the Prometheus header, add it to the context.
 		timeoutSeconds, err := getScrapeTimeoutSeconds(r, *timeoutOffset)
 		if err != nil {
@@ -210,24 +362,25 @@ func newHandler(scrapers []collector.Scraper, logger *slog.Logger) http.HandlerF
 
 		filteredScrapers := filterScrapers(scrapers, collect)
 
-		registry := prometheus.NewRegistry()
-
-		registry.MustRegister(collector.New(ctx, dsn, filteredScrapers, logger,
-			collector.EnableLockWaitTimeout(*enableExporterLockTimeout),
-			collector.SetLockWaitTimeout(*exporterLockTimeout),
-			collector.SetSlowLogFilter(*slowLogFilter),
-		))
-
 		gatherers := prometheus.Gatherers{
 			prometheus.DefaultGatherer,
-			registry,
+			newTargetGatherer(ctx, target, "client", dsn, filteredScrapers, logger, profile),
 		}
 		// Delegate http serving to Prometheus client library, which will call collector.Collect.
-		h := promhttp.HandlerFor(gatherers, promhttp.HandlerOpts{})
+		h := metricsHandlerFor(gatherers, logger)
 		h.ServeHTTP(w, r)
 	}
 }
 
+// metricsHandlerFor returns the handler serving the metrics of g. The format
+// is negotiated with the scraper; native histograms are only exposed with the
+// protobuf format.
+func metricsHandlerFor(g prometheus.Gatherer, logger *slog.Logger) http.Handler {
+	return promhttp.HandlerFor(g, promhttp.HandlerOpts{
+		ErrorLog: slog.NewLogLogger(logger.Handler(), slog.LevelError),
+	})
+}
+
 func main() {
 	// Generate ON/OFF flags for all scrapers.
 	scraperFlags := map[collector.Scraper]*bool{}
@@ -250,15 +403,11 @@ func main() {
 	flag.AddFlags(kingpin.CommandLine, promslogConfig)
 	kingpin.Version(version.Print("mysqld_exporter"))
 	kingpin.HelpFlag.Short('h')
-	kingpin.Parse()
+	command := kingpin.Parse()
 	logger := promslog.New(promslogConfig)
 
-	logger.Info("Starting mysqld_exporter", "version", version.Info())
-	logger.Info("Build context", "build_context", version.BuildContext())
-
-	var err error
-	if err = c.ReloadConfig(*configMycnf, *mysqldAddress, *mysqldUser, *tlsInsecureSkipVerify, logger); err != nil {
-		logger.Info("Error parsing host config", "file", *configMycnf, "err", err)
+	if err := checkCollectorFlags(); err != nil {
+		logger.Error("Invalid collector flags", "err", err)
 		os.Exit(1)
 	}
 
@@ -266,23 +415,142 @@ func main() {
 	enabledScrapers := []collector.Scraper{}
 	for scraper, enabled := range scraperFlags {
 		if *enabled {
-			logger.Info("Scraper enabled", "scraper", scraper.Name())
-			enabledScrapers = append(enabledScrapers, scraper)
+			enabledScrapers = append(enabledScrapers, configureScraper(scraper))
+		}
+	}
+	allScrapers := configureScrapers(slices.Collect(maps.Keys(scrapers)))
+
+	var err error
+	seriesLimits, err = parseSeriesLimits(*seriesLimit, *seriesLimitCollectors, *seriesLimitFold, allScrapers)
+	if err != nil {
+		logger.Error("Error parsing series limits", "err", err)
+		os.Exit(1)
+	}
+	metricRules, err = parseMetricRules(*metricKeep, *metricDrop, *labelDrop, *labelRename, *labelHashLength)
+	if err != nil {
+		logger.Error("Error parsing metric rules", "err", err)
+		os.Exit(1)
+	}
+	if metricRules.Privacy, err = parsePrivacy(*privacyHash, *privacyRedact, *privacySaltFile); err != nil {
+		logger.Error("Error parsing privacy settings", "err", err)
+		os.Exit(1)
+	}
+
+	if *collectorBackoffInitial > 0 {
+		collectorBackoff = collector.NewCollectorBackoff(*collectorBackoffInitial, *collectorBackoffMax)
+	}
+
+	if *replayFile != "" {
+		if replaySnapshot, err = loadSnapshot(*replayFile); err != nil {
+			logger.Error("Error loading snapshot", "file", *replayFile, "err", err)
+			os.Exit(1)
+		}
+		logger.Warn("Serving metrics from snapshot instead of a live server", "file", *replayFile, "target", replaySnapshot.Target, "time", replaySnapshot.Time)
+	}
+
+	switch command {
+	case checkCmd.FullCommand():
+		if !runCheck(context.Background(), os.Stdout, enabledScrapers, logger) {
+			os.Exit(1)
+		}
+		return
+	case grantsCmd.FullCommand():
+		printGrants(os.Stdout, enabledScrapers)
+		return
+	case otlpCmd.FullCommand():
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		err := runOTLP(ctx, enabledScrapers, logger)
+		stop()
+		if err != nil {
+			logger.Error("Error pushing metrics via OTLP", "err", err)
+			os.Exit(1)
+		}
+		return
+	case snapshotCmd.FullCommand():
+		if err := runSnapshot(context.Background(), os.Stdout, allScrapers, logger); err != nil {
+			logger.Error("Error taking snapshot", "err", err)
+			os.Exit(1)
+		}
+		return
+	case remoteWriteCmd.FullCommand():
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		err := runRemoteWrite(ctx, enabledScrapers, logger)
+		stop()
+		if err != nil {
+			logger.Error("Error pushing samples via remote write", "err", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *once {
+		ctx, cancel := context.WithTimeout(context.Background(), *onceTimeout)
+		err := runOnce(ctx, enabledScrapers, logger)
+		cancel()
+		if err != nil {
+			logger.Error("Error scraping once", "err", err)
+			os.Exit(1)
 		}
+		return
 	}
-	handlerFunc := newHandler(enabledScrapers, logger)
+
+	logger.Info("Starting mysqld_exporter", "version", version.Info())
+	logger.Info("Build context", "build_context", version.BuildContext())
+
+	if err = reloadConfig(logger); err != nil {
+		logger.Info("Error parsing host config", "file", *configMycnf, "err", err)
+		os.Exit(1)
+	}
+
+	if *tracingEndpoint != "" {
+		shutdown, err := setupTracing(context.Background())
+		if err != nil {
+			logger.Error("Error setting up tracing", "err", err)
+			os.Exit(1)
+		}
+		defer func() {
+			if err := shutdown(context.Background()); err != nil {
+				logger.Error("Error shutting down tracing", "err", err)
+			}
+		}()
+		logger.Info("Tracing scrapes", "endpoint", *tracingEndpoint, "protocol", *tracingProtocol)
+	}
+
+	for _, scraper := range enabledScrapers {
+		logger.Info("Scraper enabled", "scraper", scraper.Name())
+	}
+	handlerFunc := newHandler(enabledScrapers, logger, nil)
 	http.Handle(*metricsPath, promhttp.InstrumentMetricHandler(prometheus.DefaultRegisterer, handlerFunc))
+
+	profiles, err := loadProfiles(*profileFlags, *profilesFile, allScrapers)
+	if err != nil {
+		logger.Error("Error loading scrape profiles", "err", err)
+		os.Exit(1)
+	}
+	links := []web.LandingLinks{
+		{
+			Address: *metricsPath,
+			Text:    "Metrics",
+		},
+	}
+	for _, profile := range profiles {
+		logger.Info("Serving scrape profile", "profile", profile.Name, "path", profilePath(profile), "collectors", strings.Join(profile.Collectors, ","))
+		http.Handle(profilePath(profile), promhttp.InstrumentMetricHandler(prometheus.DefaultRegisterer, newHandler(profile.scrapers, logger, profile)))
+		links = append(links, web.LandingLinks{
+			Address: profilePath(profile),
+			Text:    "Metrics (" + profile.Name + ")",
+		})
+	}
+
 	if *metricsPath != "/" && *metricsPath != "" {
 		landingConfig := web.LandingConfig{
 			Name:        "MySQLd Exporter",
 			Description: "Prometheus Exporter for MySQL servers",
 			Version:     version.Info(),
-			Links: []web.LandingLinks{
-				{
-					Address: *metricsPath,
-					Text:    "Metrics",
-				},
-			},
+			Links: append(links, web.LandingLinks{
+				Address: "/status",
+				Text:    "Status",
+			}),
 		}
 		landingPage, err := web.NewLandingPage(landingConfig)
 		if err != nil {
@@ -292,8 +560,11 @@ func main() {
 		http.Handle("/", landingPage)
 	}
 	http.HandleFunc("/probe", handleProbe(enabledScrapers, logger))
+	http.HandleFunc("/status", handleStatus(logger))
+	http.HandleFunc("/-/healthy", handleHealthy)
+	http.HandleFunc("/-/ready", handleReady(logger))
 	http.HandleFunc("/-/reload", func(w http.ResponseWriter, r *http.Request) {
-		if err = c.ReloadConfig(*configMycnf, *mysqldAddress, *mysqldUser, *tlsInsecureSkipVerify, logger); err != nil {
+		if err = reloadConfig(logger); err != nil {
 			logger.Warn("Error reloading host config", "file", *configMycnf, "error", err)
 			return
 		}
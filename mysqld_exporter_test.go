@@ -19,6 +19,7 @@ import (
 	"io"
 	"net"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"os"
 	"os/exec"
@@ -30,6 +31,10 @@ import (
 	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	"github.com/prometheus/common/promslog"
 	"github.com/prometheus/mysqld_exporter/collector"
 )
 
@@ -84,6 +89,7 @@ func TestBin(t *testing.T) {
 	tests := []func(*testing.T, bin){
 		testLanding,
 		testProbe,
+		testHealth,
 	}
 
 	portStart := 56000
@@ -170,6 +176,8 @@ label {
           
           <li><a href="/metrics">Metrics</a></li>
           
+          <li><a href="/status">Status</a></li>
+          
         </ul>
       </div>
       
@@ -225,6 +233,44 @@ func testProbe(t *testing.T, data bin) {
 	}
 }
 
+func testHealth(t *testing.T, data bin) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// Run exporter against an address where no database is listening.
+	cmd := exec.CommandContext(
+		ctx,
+		data.path,
+		"--web.listen-address", fmt.Sprintf(":%d", data.port),
+		"--config.my-cnf=test_exporter.cnf",
+		"--mysqld.address=127.0.0.1:1",
+		"--exporter.ready_timeout=1s",
+	)
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer cmd.Wait()
+	defer cmd.Process.Kill()
+
+	body, err := waitForBody(fmt.Sprintf("http://127.0.0.1:%d/-/healthy", data.port))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(body); got != "ok" {
+		t.Fatalf("got '%s' but expected 'ok'", got)
+	}
+
+	// An unreachable database does not make the exporter unready by default.
+	resp, err := http.Get(fmt.Sprintf("http://127.0.0.1:%d/-/ready", data.port))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("got status %d but expected %d", resp.StatusCode, http.StatusOK)
+	}
+}
+
 // waitForBody is a helper function which makes http calls until http server is up
 // and then returns body of the successful call.
 func waitForBody(urlToGet string) (body []byte, err error) {
@@ -407,3 +453,35 @@ func Test_getScrapeTimeoutSeconds(t *testing.T) {
 		})
 	}
 }
+
+func TestMetricsHandlerNativeHistograms(t *testing.T) {
+	desc := prometheus.NewDesc("test_latency_seconds", "Test latency.", nil, nil)
+	h := prometheus.MustNewConstNativeHistogram(desc, 3, 1.5, map[int]int64{0: 1, 1: 2}, nil, 0, 0, 0, time.Time{})
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(constCollector{h})
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	req.Header.Set("Accept", "application/vnd.google.protobuf;proto=io.prometheus.client.MetricFamily;encoding=delimited")
+	rec := httptest.NewRecorder()
+	metricsHandlerFor(reg, promslog.NewNopLogger()).ServeHTTP(rec, req)
+
+	format := expfmt.ResponseFormat(rec.Result().Header)
+	if format.FormatType() != expfmt.TypeProtoDelim {
+		t.Fatalf("got format %s, want protobuf", format)
+	}
+	var mf dto.MetricFamily
+	if err := expfmt.NewDecoder(rec.Body, format).Decode(&mf); err != nil {
+		t.Fatal(err)
+	}
+	if got := mf.GetMetric()[0].GetHistogram().GetPositiveDelta(); !reflect.DeepEqual(got, []int64{1, 1}) {
+		t.Errorf("got positive deltas %v, want [1 1]", got)
+	}
+}
+
+// constCollector collects a fixed metric.
+type constCollector struct {
+	m prometheus.Metric
+}
+
+func (c constCollector) Describe(ch chan<- *prometheus.Desc) { ch <- c.m.Desc() }
+func (c constCollector) Collect(ch chan<- prometheus.Metric) { ch <- c.m }
@@ -0,0 +1,113 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/alecthomas/kingpin/v2"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+
+	"github.com/prometheus/mysqld_exporter/collector"
+)
+
+var (
+	once = kingpin.Flag(
+		"once",
+		"Scrape the target of the [client] section once, write the metrics to --output and exit instead of serving them.",
+	).Default("false").Bool()
+	onceOutput = kingpin.Flag(
+		"output",
+		"File to write the metrics to with --once, e.g. for the node_exporter textfile collector. The file is replaced atomically. Use - for stdout.",
+	).Default("-").String()
+	onceTimeout = kingpin.Flag(
+		"once.timeout",
+		"Timeout of the scrape with --once.",
+	).Default("30s").Duration()
+)
+
+// writeMetrics writes the metrics in the text format to path, or to stdout if
+// path is "-".
+func writeMetrics(path string, stdout io.Writer, mfs []*dto.MetricFamily) error {
+	encode := func(w io.Writer) error {
+		enc := expfmt.NewEncoder(w, expfmt.NewFormat(expfmt.TypeTextPlain))
+		for _, mf := range mfs {
+			if err := enc.Encode(mf); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if path == "-" {
+		return encode(stdout)
+	}
+	return writeFileAtomic(path, 0o644, encode)
+}
+
+// writeFileAtomic writes a file with the given permissions by writing to a
+// temporary file first and renaming it, so readers never see a partial file.
+func writeFileAtomic(path string, perm os.FileMode, write func(io.Writer) error) error {
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Chmod(perm); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// runOnce scrapes the target of the [client] section once and writes the
+// metrics. The metrics are written even if the scrape failed, so mysql_up
+// reports the failure; the scrape error is returned afterwards.
+func runOnce(ctx context.Context, scrapers []collector.Scraper, logger *slog.Logger) error {
+	if err := reloadConfig(logger); err != nil {
+		return fmt.Errorf("error parsing config %s: %w", *configMycnf, err)
+	}
+	section, ok := c.GetConfig().Sections["client"]
+	if !ok {
+		return fmt.Errorf("no [client] section in %s", *configMycnf)
+	}
+	dsn, err := section.FormDSN("")
+	if err != nil {
+		return err
+	}
+	mfs, result, err := gatherTarget(ctx, "client", dsn, scrapers, logger)
+	if err != nil {
+		return err
+	}
+	if err := writeMetrics(*onceOutput, os.Stdout, mfs); err != nil {
+		return fmt.Errorf("error writing metrics: %w", err)
+	}
+	return result.Err
+}
@@ -0,0 +1,223 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alecthomas/kingpin/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/version"
+	promBridge "go.opentelemetry.io/contrib/bridges/prometheus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/resource"
+
+	"github.com/prometheus/mysqld_exporter/collector"
+)
+
+const serverUUIDQuery = `SELECT @@server_uuid`
+
+var (
+	otlpCmd = kingpin.Command(
+		"otlp",
+		"Periodically scrape every configured target and push the metrics to an OTLP receiver.",
+	)
+	otlpEndpoint = otlpCmd.Flag(
+		"otlp.endpoint",
+		"URL of the OTLP receiver, e.g. http://collector:4317 for gRPC or https://collector:4318/v1/metrics for HTTP. Defaults to the OTEL_EXPORTER_OTLP_* environment variables.",
+	).String()
+	otlpProtocol = otlpCmd.Flag(
+		"otlp.protocol",
+		"OTLP transport protocol.",
+	).Default("grpc").Enum("grpc", "http/protobuf")
+	otlpHeaders = otlpCmd.Flag(
+		"otlp.header",
+		"Header sent with every export, e.g. Authorization=Bearer xyz. Repeatable.",
+	).Strings()
+	otlpInterval = otlpCmd.Flag(
+		"otlp.interval",
+		"Interval between pushes.",
+	).Default("60s").Duration()
+	otlpTimeout = otlpCmd.Flag(
+		"otlp.timeout",
+		"Timeout for scraping and pushing a single target.",
+	).Default("30s").Duration()
+)
+
+// newOTLPExporter returns the OTLP metric exporter configured by the flags.
+func newOTLPExporter(ctx context.Context) (sdkmetric.Exporter, error) {
+	headers := map[string]string{}
+	for _, header := range *otlpHeaders {
+		key, value, ok := strings.Cut(header, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid OTLP header %q, expected key=value", header)
+		}
+		headers[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+
+	if *otlpProtocol == "http/protobuf" {
+		opts := []otlpmetrichttp.Option{otlpmetrichttp.WithTimeout(*otlpTimeout)}
+		if *otlpEndpoint != "" {
+			opts = append(opts, otlpmetrichttp.WithEndpointURL(*otlpEndpoint))
+		}
+		if len(headers) > 0 {
+			opts = append(opts, otlpmetrichttp.WithHeaders(headers))
+		}
+		return otlpmetrichttp.New(ctx, opts...)
+	}
+	opts := []otlpmetricgrpc.Option{otlpmetricgrpc.WithTimeout(*otlpTimeout)}
+	if *otlpEndpoint != "" {
+		opts = append(opts, otlpmetricgrpc.WithEndpointURL(*otlpEndpoint))
+	}
+	if len(headers) > 0 {
+		opts = append(opts, otlpmetricgrpc.WithHeaders(headers))
+	}
+	return otlpmetricgrpc.New(ctx, opts...)
+}
+
+// targetResource returns the OpenTelemetry resource describing a target.
+func targetResource(result collector.ScrapeResult, serverUUID string) *resource.Resource {
+	attrs := []attribute.KeyValue{
+		attribute.String("service.name", "mysqld_exporter"),
+		attribute.String("service.version", version.Version),
+	}
+	if host, port, err := net.SplitHostPort(result.Target); err == nil {
+		attrs = append(attrs, attribute.String("server.address", host), attribute.String("server.port", port))
+	} else if result.Target != "" {
+		attrs = append(attrs, attribute.String("server.address", result.Target))
+	}
+	if result.Version != "" {
+		attrs = append(attrs, attribute.String("mysql.version", result.Version))
+	}
+	if result.Flavor != "" {
+		attrs = append(attrs, attribute.String("db.system.name", result.Flavor), attribute.String("mysql.flavor", result.Flavor))
+	}
+	if serverUUID != "" {
+		attrs = append(attrs, attribute.String("mysql.server_uuid", serverUUID))
+	}
+	return resource.NewSchemaless(attrs...)
+}
+
+// exportGatherer pushes the metrics of a gatherer with the given resource.
+func exportGatherer(ctx context.Context, exporter sdkmetric.Exporter, gatherer prometheus.Gatherer, res *resource.Resource) error {
+	scopeMetrics, err := promBridge.NewMetricProducer(promBridge.WithGatherer(gatherer)).Produce(ctx)
+	if err != nil {
+		return err
+	}
+	return exporter.Export(ctx, &metricdata.ResourceMetrics{Resource: res, ScopeMetrics: scopeMetrics})
+}
+
+// otlpPusher scrapes targets and pushes their metrics via OTLP.
+type otlpPusher struct {
+	exporter sdkmetric.Exporter
+	scrapers []collector.Scraper
+	logger   *slog.Logger
+
+	mu          sync.Mutex
+	serverUUIDs map[string]string
+}
+
+// serverUUID returns the server UUID of the target, cached after the first
+// successful query. MariaDB has no server UUID.
+func (p *otlpPusher) serverUUID(ctx context.Context, dsn string) string {
+	p.mu.Lock()
+	uuid, ok := p.serverUUIDs[dsn]
+	p.mu.Unlock()
+	if ok {
+		return uuid
+	}
+
+	db, err := sql.Open("mysql", dsn)
+	if err != nil {
+		return ""
+	}
+	defer db.Close()
+	if err := db.QueryRowContext(ctx, serverUUIDQuery).Scan(&uuid); err != nil {
+		p.logger.Debug("Error querying server UUID", "err", err)
+		return ""
+	}
+	p.mu.Lock()
+	p.serverUUIDs[dsn] = uuid
+	p.mu.Unlock()
+	return uuid
+}
+
+// push scrapes the target of a config section once and exports the metrics.
+func (p *otlpPusher) push(ctx context.Context, authModule, dsn string) error {
+	ctx, cancel := context.WithTimeout(ctx, *otlpTimeout)
+	defer cancel()
+
+	mfs, result, err := gatherTarget(ctx, authModule, dsn, p.scrapers, p.logger)
+	if err != nil {
+		return err
+	}
+	if result.Err != nil {
+		p.logger.Warn("Error scraping target", "target", result.Target, "err", result.Err)
+	}
+
+	uuid := ""
+	if result.Err == nil {
+		uuid = p.serverUUID(ctx, dsn)
+	}
+	gatherer := prometheus.GathererFunc(func() ([]*dto.MetricFamily, error) { return mfs, nil })
+	return exportGatherer(ctx, p.exporter, gatherer, targetResource(result, uuid))
+}
+
+// runOTLP pushes the metrics of every configured target every interval until
+// ctx is done.
+func runOTLP(ctx context.Context, scrapers []collector.Scraper, logger *slog.Logger) error {
+	if err := reloadConfig(logger); err != nil {
+		return fmt.Errorf("error parsing config %s: %w", *configMycnf, err)
+	}
+	exporter, err := newOTLPExporter(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), *otlpTimeout)
+		defer cancel()
+		if err := exporter.Shutdown(ctx); err != nil {
+			logger.Error("Error shutting down OTLP exporter", "err", err)
+		}
+	}()
+
+	p := &otlpPusher{exporter: exporter, scrapers: scrapers, logger: logger, serverUUIDs: map[string]string{}}
+	logger.Info("Pushing metrics via OTLP", "protocol", *otlpProtocol, "interval", *otlpInterval)
+	ticker := time.NewTicker(*otlpInterval)
+	defer ticker.Stop()
+	for {
+		forEachTarget(logger, func(section, dsn string) {
+			if err := p.push(ctx, section, dsn); err != nil {
+				logger.Error("Error pushing metrics", "section", section, "err", err)
+			}
+		})
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	collectorpb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/prometheus/mysqld_exporter/collector"
+)
+
+func TestExportGathererOTLP(t *testing.T) {
+	requests := make(chan *collectorpb.ExportMetricsServiceRequest, 1)
+	headers := make(chan http.Header, 1)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Error(err)
+		}
+		req := &collectorpb.ExportMetricsServiceRequest{}
+		if err := proto.Unmarshal(body, req); err != nil {
+			t.Error(err)
+		}
+		requests <- req
+		headers <- r.Header
+		resp, _ := proto.Marshal(&collectorpb.ExportMetricsServiceResponse{})
+		w.Header().Set("Content-Type", "application/x-protobuf")
+		_, _ = w.Write(resp)
+	}))
+	defer receiver.Close()
+
+	*otlpProtocol = "http/protobuf"
+	*otlpEndpoint = receiver.URL + "/v1/metrics"
+	*otlpHeaders = []string{"Authorization=Bearer secret"}
+	defer func() { *otlpProtocol, *otlpEndpoint, *otlpHeaders = "grpc", "", nil }()
+
+	ctx := context.Background()
+	exporter, err := newOTLPExporter(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer exporter.Shutdown(ctx)
+
+	registry := prometheus.NewRegistry()
+	up := prometheus.NewGauge(prometheus.GaugeOpts{Name: "mysql_up", Help: "Whether the MySQL server is up."})
+	up.Set(1)
+	registry.MustRegister(up)
+
+	res := targetResource(collector.ScrapeResult{Target: "db:3306", Version: "8.0.36", Flavor: "mysql"}, "3e11fa47-71ca-11e1-9e33-c80aa9429562")
+	if err := exportGatherer(ctx, exporter, registry, res); err != nil {
+		t.Fatal(err)
+	}
+
+	req := <-requests
+	if got := (<-headers).Get("Authorization"); got != "Bearer secret" {
+		t.Errorf("unexpected Authorization header %q", got)
+	}
+	if len(req.ResourceMetrics) != 1 {
+		t.Fatalf("expected 1 resource, got %d", len(req.ResourceMetrics))
+	}
+	attrs := map[string]string{}
+	for _, kv := range req.ResourceMetrics[0].Resource.Attributes {
+		attrs[kv.Key] = kv.Value.GetStringValue()
+	}
+	for key, want := range map[string]string{
+		"service.name":      "mysqld_exporter",
+		"server.address":    "db",
+		"server.port":       "3306",
+		"db.system.name":    "mysql",
+		"mysql.version":     "8.0.36",
+		"mysql.server_uuid": "3e11fa47-71ca-11e1-9e33-c80aa9429562",
+	} {
+		if attrs[key] != want {
+			t.Errorf("attribute %s: got %q, want %q", key, attrs[key], want)
+		}
+	}
+
+	var names []string
+	for _, sm := range req.ResourceMetrics[0].ScopeMetrics {
+		for _, m := range sm.Metrics {
+			names = append(names, m.Name)
+			if m.Name == "mysql_up" && m.GetGauge().GetDataPoints()[0].GetAsDouble() != 1 {
+				t.Errorf("unexpected mysql_up data points %v", m.GetGauge().GetDataPoints())
+			}
+		}
+	}
+	if len(names) != 1 || names[0] != "mysql_up" {
+		t.Errorf("unexpected metrics %v", names)
+	}
+}
+
+func TestTargetResourceWithoutUUID(t *testing.T) {
+	res := targetResource(collector.ScrapeResult{Target: "/var/run/mysqld/mysqld.sock", Flavor: "mariadb"}, "")
+	attrs := map[string]string{}
+	for _, kv := range res.Attributes() {
+		attrs[string(kv.Key)] = kv.Value.AsString()
+	}
+	if attrs["server.address"] != "/var/run/mysqld/mysqld.sock" || attrs["db.system.name"] != "mariadb" {
+		t.Errorf("unexpected attributes %v", attrs)
+	}
+	if _, ok := attrs["mysql.server_uuid"]; ok {
+		t.Error("unexpected server UUID attribute")
+	}
+	if _, ok := attrs["server.port"]; ok {
+		t.Error("unexpected server port attribute")
+	}
+}
@@ -20,14 +20,11 @@ import (
 	"net/http"
 	"time"
 
-	"github.com/prometheus/client_golang/prometheus"
-	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/prometheus/mysqld_exporter/collector"
 )
 
 func handleProbe(scrapers []collector.Scraper, logger *slog.Logger) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		ctx := r.Context()
 		params := r.URL.Query()
 		target := params.Get("target")
 		if target == "" {
@@ -55,6 +52,9 @@ func handleProbe(scrapers []collector.Scraper, logger *slog.Logger) http.Handler
 			return
 		}
 
+		ctx, span := startScrapeSpan(r, target, authModule)
+		defer span.End()
+
 		// If a timeout is configured via the Prometheus header, add it to the context.
 		timeoutSeconds, err := getScrapeTimeoutSeconds(r, *timeoutOffset)
 		if err != nil {
@@ -71,14 +71,9 @@ func handleProbe(scrapers []collector.Scraper, logger *slog.Logger) http.Handler
 
 		filteredScrapers := filterScrapers(scrapers, collectParams)
 
-		registry := prometheus.NewRegistry()
-		registry.MustRegister(collector.New(ctx, dsn, filteredScrapers, logger,
-			collector.EnableLockWaitTimeout(*enableExporterLockTimeout),
-			collector.SetLockWaitTimeout(*exporterLockTimeout),
-			collector.SetSlowLogFilter(*slowLogFilter),
-		))
+		gatherer := newTargetGatherer(ctx, target, authModule, dsn, filteredScrapers, logger, nil)
 
-		h := promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+		h := metricsHandlerFor(gatherer, logger)
 		h.ServeHTTP(w, r)
 	}
 }
@@ -0,0 +1,245 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alecthomas/kingpin/v2"
+	"go.yaml.in/yaml/v2"
+
+	"github.com/prometheus/mysqld_exporter/collector"
+)
+
+var (
+	profileFlags = kingpin.Flag(
+		"web.profile",
+		"Serve a scrape profile under <web.telemetry-path>/<name>, in the form name=collector,..., e.g. fast=global_status,slave_status. Repeatable.",
+	).Strings()
+	profilesFile = kingpin.Flag(
+		"web.profiles-file",
+		"Path to a YAML file defining scrape profiles with their collectors and tunables.",
+	).String()
+)
+
+var profileNameRegex = regexp.MustCompile(`^[a-z0-9_-]+$`)
+
+// reservedPaths are served by the exporter besides the telemetry path.
+var reservedPaths = []string{"/", "/probe", "/status", "/-/healthy", "/-/ready", "/-/reload"}
+
+// profileCollectorOptions lists the collector flags a profile may override,
+// with a function applying the value to the scrapers it configures.
+var profileCollectorOptions = map[string]func(collector.Scraper, string) (collector.Scraper, error){
+	"info_schema.innodb_trx.top_n": func(s collector.Scraper, value string) (collector.Scraper, error) {
+		scraper, ok := s.(collector.ScrapeInnodbTrx)
+		if !ok {
+			return s, nil
+		}
+		opts := collector.DefaultInnodbTrxOptions()
+		if scraper.Options != nil {
+			opts = *scraper.Options
+		}
+		var err error
+		opts.TopN, err = strconv.Atoi(value)
+		return collector.ScrapeInnodbTrx{Options: &opts}, err
+	},
+	"info_schema.processlist.min_time": func(s collector.Scraper, value string) (collector.Scraper, error) {
+		scraper, ok := s.(collector.ScrapeProcesslist)
+		if !ok {
+			return s, nil
+		}
+		opts := collector.DefaultProcesslistOptions()
+		if scraper.Options != nil {
+			opts = *scraper.Options
+		}
+		var err error
+		opts.MinTime, err = strconv.Atoi(value)
+		return collector.ScrapeProcesslist{Options: &opts}, err
+	},
+	"info_schema.tables.databases": func(s collector.Scraper, value string) (collector.Scraper, error) {
+		if _, ok := s.(collector.ScrapeTableSchema); !ok {
+			return s, nil
+		}
+		var opts collector.TableSchemaOptions
+		if value != "*" {
+			opts.Databases = strings.Split(value, ",")
+		}
+		return collector.ScrapeTableSchema{Options: &opts}, nil
+	},
+	"perf_schema.eventsstatements.limit": func(s collector.Scraper, value string) (collector.Scraper, error) {
+		return overrideEventsStatements(s, func(opts *collector.PerfEventsStatementsOptions) (err error) {
+			opts.Limit, err = strconv.Atoi(value)
+			return err
+		})
+	},
+	"perf_schema.eventsstatements.timelimit": func(s collector.Scraper, value string) (collector.Scraper, error) {
+		return overrideEventsStatements(s, func(opts *collector.PerfEventsStatementsOptions) (err error) {
+			opts.TimeLimit, err = strconv.Atoi(value)
+			return err
+		})
+	},
+	"perf_schema.eventsstatements.digest_text_limit": func(s collector.Scraper, value string) (collector.Scraper, error) {
+		return overrideEventsStatements(s, func(opts *collector.PerfEventsStatementsOptions) (err error) {
+			opts.DigestTextLimit, err = strconv.Atoi(value)
+			return err
+		})
+	},
+}
+
+// overrideEventsStatements returns s with its options changed by set if it is
+// a ScrapePerfEventsStatements, s otherwise.
+func overrideEventsStatements(s collector.Scraper, set func(*collector.PerfEventsStatementsOptions) error) (collector.Scraper, error) {
+	scraper, ok := s.(collector.ScrapePerfEventsStatements)
+	if !ok {
+		return s, nil
+	}
+	opts := collector.DefaultPerfEventsStatementsOptions()
+	if scraper.Options != nil {
+		opts = *scraper.Options
+	}
+	if err := set(&opts); err != nil {
+		return nil, err
+	}
+	return collector.ScrapePerfEventsStatements{Options: &opts}, nil
+}
+
+// scrapeProfile is a named set of collectors with its own tunables, served
+// on its own path. Unset tunables default to the flags.
+type scrapeProfile struct {
+	Name                  string         `yaml:"-"`
+	Collectors            []string       `yaml:"collectors"`
+	SeriesLimit           *int           `yaml:"series_limit"`
+	DeadlineMargin        *time.Duration `yaml:"deadline_margin"`
+	LoadSheddingThreshold *float64       `yaml:"load_shedding_threshold"`
+	LockWaitTimeout       *int           `yaml:"lock_wait_timeout"`
+	// CollectorOptions overrides collector flags, by name without the
+	// collect. prefix, e.g. info_schema.tables.databases.
+	CollectorOptions map[string]string `yaml:"collector_options"`
+
+	scrapers []collector.Scraper
+}
+
+// profilesConfig is the content of --web.profiles-file.
+type profilesConfig struct {
+	Profiles map[string]*scrapeProfile `yaml:"profiles"`
+}
+
+// opts returns the options overriding the flags for the profile.
+func (p *scrapeProfile) opts() []collector.ExporterOpt {
+	var opts []collector.ExporterOpt
+	if p.SeriesLimit != nil {
+		limits := seriesLimits
+		limits.Default = *p.SeriesLimit
+		opts = append(opts, collector.SetSeriesLimits(limits))
+	}
+	if p.DeadlineMargin != nil {
+		opts = append(opts, collector.SetDeadlineMargin(*p.DeadlineMargin))
+	}
+	if p.LoadSheddingThreshold != nil {
+		opts = append(opts, collector.SetLoadShedding(collector.LoadShedding{
+			Variable:  *loadSheddingVariable,
+			Threshold: *p.LoadSheddingThreshold,
+		}))
+	}
+	if p.LockWaitTimeout != nil {
+		opts = append(opts, collector.EnableLockWaitTimeout(true), collector.SetLockWaitTimeout(*p.LockWaitTimeout))
+	}
+	return opts
+}
+
+// loadProfiles returns the profiles defined by the --web.profile flags and
+// the profiles file, ordered by name. Profiles may use any of the scrapers,
+// whether enabled or not.
+func loadProfiles(flags []string, file string, scrapers []collector.Scraper) ([]*scrapeProfile, error) {
+	profiles := map[string]*scrapeProfile{}
+	if file != "" {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		var cfg profilesConfig
+		if err := yaml.UnmarshalStrict(content, &cfg); err != nil {
+			return nil, fmt.Errorf("error parsing %s: %w", file, err)
+		}
+		if cfg.Profiles != nil {
+			profiles = cfg.Profiles
+		}
+	}
+	for _, flag := range flags {
+		name, collectors, ok := strings.Cut(flag, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid profile %q, expected name=collector,...", flag)
+		}
+		if _, ok := profiles[name]; ok {
+			return nil, fmt.Errorf("duplicate profile %q", name)
+		}
+		profiles[name] = &scrapeProfile{Collectors: strings.Split(collectors, ",")}
+	}
+
+	known := map[string]bool{}
+	for _, scraper := range scrapers {
+		known[scraper.Name()] = true
+	}
+	var result []*scrapeProfile
+	for name, profile := range profiles {
+		if !profileNameRegex.MatchString(name) {
+			return nil, fmt.Errorf("invalid profile name %q, must match %s", name, profileNameRegex)
+		}
+		if profile == nil || len(profile.Collectors) == 0 {
+			return nil, fmt.Errorf("profile %q has no collectors", name)
+		}
+		names := make([]string, 0, len(profile.Collectors))
+		for _, c := range profile.Collectors {
+			c = strings.TrimPrefix(c, "collect.")
+			if !known[c] {
+				return nil, fmt.Errorf("profile %q: unknown collector %q", name, c)
+			}
+			names = append(names, c)
+		}
+		profile.Name = name
+		if path := profilePath(profile); path == *metricsPath || slices.Contains(reservedPaths, path) {
+			return nil, fmt.Errorf("profile %q would be served on %s, which is already in use", name, path)
+		}
+		profile.scrapers = filterScrapers(scrapers, names)
+		for option, value := range profile.CollectorOptions {
+			override, ok := profileCollectorOptions[strings.TrimPrefix(option, "collect.")]
+			if !ok {
+				return nil, fmt.Errorf("profile %q: unsupported collector option %q", name, option)
+			}
+			for i, scraper := range profile.scrapers {
+				overridden, err := override(scraper, value)
+				if err != nil {
+					return nil, fmt.Errorf("profile %q: invalid value %q of collector option %q: %w", name, value, option, err)
+				}
+				profile.scrapers[i] = overridden
+			}
+		}
+		result = append(result, profile)
+	}
+	slices.SortFunc(result, func(a, b *scrapeProfile) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return result, nil
+}
+
+// profilePath returns the path a profile is served on.
+func profilePath(profile *scrapeProfile) string {
+	return strings.TrimSuffix(*metricsPath, "/") + "/" + profile.Name
+}
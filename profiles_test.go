@@ -0,0 +1,139 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/promslog"
+
+	"github.com/prometheus/mysqld_exporter/collector"
+)
+
+var profileScrapers = []collector.Scraper{
+	collector.ScrapeGlobalStatus{},
+	collector.ScrapeGlobalVariables{},
+	collector.ScrapeSlaveStatus{},
+	collector.ScrapeTableSchema{},
+}
+
+func TestLoadProfiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "profiles.yml")
+	content := `profiles:
+  slow:
+    collectors: [info_schema.tables]
+    series_limit: 5000
+    deadline_margin: 1s
+    collector_options:
+      info_schema.tables.databases: app
+      perf_schema.eventsstatements.limit: 100
+`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	profiles, err := loadProfiles([]string{"fast=global_status,collect.slave_status"}, path, profileScrapers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(profiles) != 2 || profiles[0].Name != "fast" || profiles[1].Name != "slow" {
+		t.Fatalf("unexpected profiles %+v", profiles)
+	}
+	if got := len(profiles[0].scrapers); got != 2 {
+		t.Errorf("fast profile has %d scrapers, want 2", got)
+	}
+	if got := len(profiles[0].opts()); got != 0 {
+		t.Errorf("fast profile has %d options, want 0", got)
+	}
+	slow := profiles[1]
+	if *slow.SeriesLimit != 5000 || *slow.DeadlineMargin != time.Second || len(slow.opts()) != 2 {
+		t.Errorf("unexpected tunables of slow profile %+v", slow)
+	}
+	if tables := slow.scrapers[0].(collector.ScrapeTableSchema); !slices.Equal(tables.Options.Databases, []string{"app"}) {
+		t.Errorf("unexpected table schema options of slow profile %+v", tables.Options)
+	}
+
+	for _, flags := range [][]string{
+		{"fast"},
+		{"Fast=global_status"},
+		{"fast=global_status,nope"},
+		{"slow=global_status"},
+		{"probe=global_status"},
+		{"status=global_status"},
+	} {
+		if _, err := loadProfiles(flags, path, profileScrapers); err == nil {
+			t.Errorf("loadProfiles(%q) did not fail", flags)
+		}
+	}
+
+	for _, content := range []string{
+		"profiles:\n  slow:\n    collectors: [info_schema.tables]\n    collector_options:\n      heartbeat.table: pt\n",
+		"profiles:\n  slow:\n    collectors: [info_schema.processlist]\n    collector_options:\n      info_schema.processlist.min_time: soon\n",
+	} {
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := loadProfiles(nil, path, append(profileScrapers, collector.ScrapeProcesslist{})); err == nil {
+			t.Errorf("loadProfiles(%q) did not fail", content)
+		}
+	}
+}
+
+func TestProfileHandler(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+	if err := os.WriteFile(path, []byte(testSnapshot), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	snapshot, err := loadSnapshot(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	replaySnapshot = snapshot
+	defer func() { replaySnapshot = nil }()
+	if err := reloadConfig(promslog.NewNopLogger()); err != nil {
+		t.Fatal(err)
+	}
+
+	profiles, err := loadProfiles([]string{"fast=global_status"}, "", profileScrapers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	limit := 1
+	profiles[0].SeriesLimit = &limit
+
+	rec := httptest.NewRecorder()
+	newHandler(profiles[0].scrapers, promslog.NewNopLogger(), profiles[0])(rec, httptest.NewRequest(http.MethodGet, profilePath(profiles[0]), nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("unexpected status %d", rec.Code)
+	}
+	body := rec.Body.String()
+	for _, want := range []string{
+		`mysql_exporter_collector_success{collector="collect.global_status"} 1`,
+		`mysql_exporter_collector_series_dropped{collector="collect.global_status"} 0`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("missing %q in response", want)
+		}
+	}
+	if strings.Contains(body, "collect.global_variables") {
+		t.Error("collector outside the profile was scraped")
+	}
+}
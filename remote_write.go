@@ -0,0 +1,305 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alecthomas/kingpin/v2"
+	"github.com/klauspost/compress/snappy"
+	"github.com/prometheus/common/version"
+
+	"github.com/prometheus/mysqld_exporter/collector"
+)
+
+var (
+	remoteWriteCmd = kingpin.Command(
+		"remote-write",
+		"Periodically scrape every configured target and push the samples via Prometheus remote write.",
+	)
+	remoteWriteURL = remoteWriteCmd.Flag(
+		"remote_write.url",
+		"URL of the remote write endpoint, e.g. https://prometheus:9090/api/v1/write.",
+	).Required().URL()
+	remoteWriteProtocol = remoteWriteCmd.Flag(
+		"remote_write.protocol",
+		"Remote write protocol version.",
+	).Default("v1").Enum("v1", "v2")
+	remoteWriteInterval = remoteWriteCmd.Flag(
+		"remote_write.interval",
+		"Interval between scrapes.",
+	).Default("60s").Duration()
+	remoteWriteTimeout = remoteWriteCmd.Flag(
+		"remote_write.timeout",
+		"Timeout for scraping a target and for each remote write request.",
+	).Default("30s").Duration()
+	remoteWriteJob = remoteWriteCmd.Flag(
+		"remote_write.job",
+		"Value of the job label of the pushed series.",
+	).Default("mysql").String()
+	remoteWriteUsername = remoteWriteCmd.Flag(
+		"remote_write.basic_auth.username",
+		"Username for basic authentication.",
+	).String()
+	remoteWritePasswordFile = remoteWriteCmd.Flag(
+		"remote_write.basic_auth.password_file",
+		"File containing the password for basic authentication.",
+	).String()
+	remoteWriteBearerTokenFile = remoteWriteCmd.Flag(
+		"remote_write.bearer_token_file",
+		"File containing the bearer token.",
+	).String()
+	remoteWriteWALDir = remoteWriteCmd.Flag(
+		"remote_write.wal.dir",
+		"Directory in which requests are stored until they have been sent.",
+	).Default("data/remote_write").String()
+	remoteWriteWALMaxAge = remoteWriteCmd.Flag(
+		"remote_write.wal.max_age",
+		"Requests that could not be sent for this long are discarded.",
+	).Default("2h").Duration()
+)
+
+// errRemoteWriteRejected marks requests the endpoint will never accept.
+var errRemoteWriteRejected = errors.New("request rejected")
+
+// remoteWriteClient sends requests to a remote write endpoint.
+type remoteWriteClient struct {
+	url         string
+	username    string
+	password    string
+	bearerToken string
+	client      *http.Client
+}
+
+func newRemoteWriteClient(u *url.URL, username, passwordFile, bearerTokenFile string, timeout time.Duration) (*remoteWriteClient, error) {
+	c := &remoteWriteClient{url: u.String(), username: username, client: &http.Client{Timeout: timeout}}
+	if bearerTokenFile != "" && (username != "" || passwordFile != "") {
+		return nil, errors.New("basic authentication and bearer token are mutually exclusive")
+	}
+	if passwordFile != "" {
+		password, err := os.ReadFile(passwordFile)
+		if err != nil {
+			return nil, err
+		}
+		c.password = strings.TrimSpace(string(password))
+	}
+	if bearerTokenFile != "" {
+		token, err := os.ReadFile(bearerTokenFile)
+		if err != nil {
+			return nil, err
+		}
+		c.bearerToken = strings.TrimSpace(string(token))
+	}
+	return c, nil
+}
+
+// send sends a snappy compressed request of the given protocol version.
+// Errors wrapping errRemoteWriteRejected must not be retried.
+func (c *remoteWriteClient) send(ctx context.Context, protocol string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Encoding", "snappy")
+	req.Header.Set("User-Agent", "mysqld_exporter/"+version.Version)
+	if protocol == "v2" {
+		req.Header.Set("Content-Type", "application/x-protobuf;proto=io.prometheus.write.v2.Request")
+		req.Header.Set("X-Prometheus-Remote-Write-Version", "2.0.0")
+	} else {
+		req.Header.Set("Content-Type", "application/x-protobuf")
+		req.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+	}
+	if c.username != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+	if c.bearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.bearerToken)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 == 2 {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	err = fmt.Errorf("server returned %s: %s", resp.Status, bytes.TrimSpace(msg))
+	if resp.StatusCode/100 == 4 && resp.StatusCode != http.StatusTooManyRequests {
+		return fmt.Errorf("%w: %w", errRemoteWriteRejected, err)
+	}
+	return err
+}
+
+// remoteWriteWAL stores requests on disk until they have been sent, so they
+// survive endpoint outages and restarts. Each request is a file named after
+// its creation time, with the protocol version as extension.
+type remoteWriteWAL struct {
+	dir    string
+	maxAge time.Duration
+	logger *slog.Logger
+}
+
+func newRemoteWriteWAL(dir string, maxAge time.Duration, logger *slog.Logger) (*remoteWriteWAL, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	return &remoteWriteWAL{dir: dir, maxAge: maxAge, logger: logger}, nil
+}
+
+// write atomically stores a compressed request.
+func (w *remoteWriteWAL) write(t time.Time, protocol string, body []byte) error {
+	name := filepath.Join(w.dir, fmt.Sprintf("%020d.%s", t.UnixNano(), protocol))
+	tmp := name + ".tmp"
+	if err := os.WriteFile(tmp, body, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, name)
+}
+
+// replay sends the stored requests oldest first and removes those that were
+// sent, rejected or have expired. It stops at the first request that can be
+// retried later.
+func (w *remoteWriteWAL) replay(now time.Time, send func(protocol string, body []byte) error) error {
+	entries, err := os.ReadDir(w.dir)
+	if err != nil {
+		return err
+	}
+	expired := 0
+	for _, entry := range entries {
+		stem, protocol, ok := strings.Cut(entry.Name(), ".")
+		if !ok || !slices.Contains([]string{"v1", "v2"}, protocol) {
+			continue
+		}
+		path := filepath.Join(w.dir, entry.Name())
+		created, err := strconv.ParseInt(stem, 10, 64)
+		if err != nil {
+			continue
+		}
+		if now.Sub(time.Unix(0, created)) > w.maxAge {
+			expired++
+			_ = os.Remove(path)
+			continue
+		}
+		body, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if err := send(protocol, body); err != nil {
+			if !errors.Is(err, errRemoteWriteRejected) {
+				return err
+			}
+			w.logger.Error("Dropping request rejected by remote write endpoint", "file", entry.Name(), "err", err)
+		}
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+	}
+	if expired > 0 {
+		w.logger.Warn("Discarded expired remote write requests", "count", expired)
+	}
+	return nil
+}
+
+// gatherSeries scrapes every configured target once and returns the series
+// labeled with the job and the target as instance.
+func gatherSeries(ctx context.Context, scrapers []collector.Scraper, logger *slog.Logger) []rwSeries {
+	var (
+		mu     sync.Mutex
+		series []rwSeries
+	)
+	forEachTarget(logger, func(section, dsn string) {
+		ctx, cancel := context.WithTimeout(ctx, *remoteWriteTimeout)
+		defer cancel()
+		mfs, result, err := gatherTarget(ctx, section, dsn, scrapers, logger)
+		if err != nil {
+			logger.Error("Error gathering metrics", "section", section, "err", err)
+		}
+		if result.Err != nil {
+			logger.Warn("Error scraping target", "section", section, "err", result.Err)
+		}
+		instance := result.Target
+		if instance == "" {
+			instance = section
+		}
+		labels := []rwLabel{{"instance", instance}, {"job", *remoteWriteJob}}
+		now := time.Now().UnixMilli()
+		mu.Lock()
+		defer mu.Unlock()
+		for _, mf := range mfs {
+			series = append(series, familySeries(mf, labels, now)...)
+		}
+	})
+	return series
+}
+
+// runRemoteWrite scrapes every configured target every interval and pushes
+// the samples until ctx is done.
+func runRemoteWrite(ctx context.Context, scrapers []collector.Scraper, logger *slog.Logger) error {
+	if err := reloadConfig(logger); err != nil {
+		return fmt.Errorf("error parsing config %s: %w", *configMycnf, err)
+	}
+	client, err := newRemoteWriteClient(*remoteWriteURL, *remoteWriteUsername, *remoteWritePasswordFile, *remoteWriteBearerTokenFile, *remoteWriteTimeout)
+	if err != nil {
+		return err
+	}
+	wal, err := newRemoteWriteWAL(*remoteWriteWALDir, *remoteWriteWALMaxAge, logger)
+	if err != nil {
+		return err
+	}
+
+	logger.Info("Pushing samples via remote write", "protocol", *remoteWriteProtocol, "interval", *remoteWriteInterval, "wal", *remoteWriteWALDir)
+	ticker := time.NewTicker(*remoteWriteInterval)
+	defer ticker.Stop()
+	for {
+		series := gatherSeries(ctx, scrapers, logger)
+		var req []byte
+		if *remoteWriteProtocol == "v2" {
+			req = encodeWriteRequestV2(series)
+		} else {
+			req = encodeWriteRequestV1(series)
+		}
+		if err := wal.write(time.Now(), *remoteWriteProtocol, snappy.Encode(nil, req)); err != nil {
+			logger.Error("Error writing remote write request", "err", err)
+		}
+		err := wal.replay(time.Now(), func(protocol string, body []byte) error {
+			return client.send(ctx, protocol, body)
+		})
+		if err != nil {
+			logger.Warn("Error sending remote write requests, retrying next interval", "err", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,274 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Encoding of remote write requests. The messages are encoded by hand to
+// avoid depending on the Prometheus server module for the generated code.
+
+package main
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"strconv"
+
+	dto "github.com/prometheus/client_model/go"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// rwLabel is a label of a remote write series.
+type rwLabel struct {
+	name, value string
+}
+
+// rwSeries is a single sample or native histogram of a series.
+type rwSeries struct {
+	// labels are sorted by name and include __name__.
+	labels    []rwLabel
+	value     float64
+	histogram *dto.Histogram
+	timestamp int64
+	// family, help and metricType describe the metric family of the series.
+	family     string
+	help       string
+	metricType dto.MetricType
+}
+
+// familySeries converts a metric family to remote write series. The extra
+// labels override labels of the same name. Samples without a timestamp get
+// the timestamp now in milliseconds.
+func familySeries(mf *dto.MetricFamily, extra []rwLabel, now int64) []rwSeries {
+	var series []rwSeries
+	for _, m := range mf.Metric {
+		ts := now
+		if m.TimestampMs != nil {
+			ts = m.GetTimestampMs()
+		}
+		add := func(name string, value float64, h *dto.Histogram, labels ...rwLabel) {
+			all := []rwLabel{{"__name__", name}}
+			for _, l := range m.Label {
+				all = append(all, rwLabel{l.GetName(), l.GetValue()})
+			}
+			all = append(all, labels...)
+			// Like Prometheus, keep a collector label clashing with an extra
+			// label as exported_<name>.
+			for _, e := range extra {
+				if i := slices.IndexFunc(all, func(l rwLabel) bool { return l.name == e.name }); i >= 0 {
+					exported := "exported_" + e.name
+					for slices.ContainsFunc(all, func(l rwLabel) bool { return l.name == exported }) {
+						exported = "exported_" + exported
+					}
+					all[i].name = exported
+				}
+				all = append(all, e)
+			}
+			slices.SortFunc(all, func(a, b rwLabel) int { return cmp.Compare(a.name, b.name) })
+			series = append(series, rwSeries{
+				labels: all, value: value, histogram: h, timestamp: ts,
+				family: mf.GetName(), help: mf.GetHelp(), metricType: mf.GetType(),
+			})
+		}
+
+		name := mf.GetName()
+		switch mf.GetType() {
+		case dto.MetricType_COUNTER:
+			add(name, m.Counter.GetValue(), nil)
+		case dto.MetricType_GAUGE:
+			add(name, m.Gauge.GetValue(), nil)
+		case dto.MetricType_SUMMARY:
+			for _, q := range m.Summary.Quantile {
+				add(name, q.GetValue(), nil, rwLabel{"quantile", formatFloat(q.GetQuantile())})
+			}
+			add(name+"_sum", m.Summary.GetSampleSum(), nil)
+			add(name+"_count", float64(m.Summary.GetSampleCount()), nil)
+		case dto.MetricType_HISTOGRAM, dto.MetricType_GAUGE_HISTOGRAM:
+			if m.Histogram.Schema != nil {
+				add(name, 0, m.Histogram)
+				continue
+			}
+			inf := false
+			for _, b := range m.Histogram.Bucket {
+				inf = inf || math.IsInf(b.GetUpperBound(), 1)
+				add(name+"_bucket", float64(b.GetCumulativeCount()), nil, rwLabel{"le", formatFloat(b.GetUpperBound())})
+			}
+			if !inf {
+				add(name+"_bucket", float64(m.Histogram.GetSampleCount()), nil, rwLabel{"le", "+Inf"})
+			}
+			add(name+"_sum", m.Histogram.GetSampleSum(), nil)
+			add(name+"_count", float64(m.Histogram.GetSampleCount()), nil)
+		default:
+			add(name, m.Untyped.GetValue(), nil)
+		}
+	}
+	return series
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// rwMetricType returns the remote write metric type, which is the same in
+// both protocol versions.
+func rwMetricType(t dto.MetricType) uint64 {
+	switch t {
+	case dto.MetricType_COUNTER:
+		return 1
+	case dto.MetricType_GAUGE:
+		return 2
+	case dto.MetricType_HISTOGRAM:
+		return 3
+	case dto.MetricType_GAUGE_HISTOGRAM:
+		return 4
+	case dto.MetricType_SUMMARY:
+		return 5
+	default:
+		return 0
+	}
+}
+
+// encodeWriteRequestV1 encodes a prometheus.WriteRequest of remote write 1.0.
+func encodeWriteRequestV1(series []rwSeries) []byte {
+	var b []byte
+	seen := map[string]bool{}
+	for _, s := range series {
+		var ts []byte
+		for _, l := range s.labels {
+			var label []byte
+			label = appendString(label, 1, l.name)
+			label = appendString(label, 2, l.value)
+			ts = appendMessage(ts, 1, label)
+		}
+		if s.histogram != nil {
+			ts = appendMessage(ts, 4, encodeHistogram(s.histogram, s.timestamp))
+		} else {
+			ts = appendMessage(ts, 2, encodeSample(s.value, s.timestamp))
+		}
+		b = appendMessage(b, 1, ts)
+
+		if !seen[s.family] {
+			seen[s.family] = true
+			var md []byte
+			md = appendVarint(md, 1, rwMetricType(s.metricType))
+			md = appendString(md, 2, s.family)
+			md = appendString(md, 4, s.help)
+			b = appendMessage(b, 3, md)
+		}
+	}
+	return b
+}
+
+// encodeWriteRequestV2 encodes an io.prometheus.write.v2.Request of remote
+// write 2.0.
+func encodeWriteRequestV2(series []rwSeries) []byte {
+	symbols := []string{""}
+	refs := map[string]uint64{"": 0}
+	ref := func(s string) uint64 {
+		r, ok := refs[s]
+		if !ok {
+			r = uint64(len(symbols))
+			refs[s] = r
+			symbols = append(symbols, s)
+		}
+		return r
+	}
+
+	var timeseries []byte
+	for _, s := range series {
+		var labelRefs []byte
+		for _, l := range s.labels {
+			labelRefs = protowire.AppendVarint(labelRefs, ref(l.name))
+			labelRefs = protowire.AppendVarint(labelRefs, ref(l.value))
+		}
+		var ts []byte
+		ts = appendMessage(ts, 1, labelRefs)
+		if s.histogram != nil {
+			ts = appendMessage(ts, 3, encodeHistogram(s.histogram, s.timestamp))
+		} else {
+			ts = appendMessage(ts, 2, encodeSample(s.value, s.timestamp))
+		}
+		var md []byte
+		md = appendVarint(md, 1, rwMetricType(s.metricType))
+		md = appendVarint(md, 3, ref(s.help))
+		ts = appendMessage(ts, 5, md)
+		timeseries = appendMessage(timeseries, 5, ts)
+	}
+
+	var b []byte
+	for _, s := range symbols {
+		b = appendString(b, 4, s)
+	}
+	return append(b, timeseries...)
+}
+
+func encodeSample(value float64, timestamp int64) []byte {
+	var b []byte
+	b = appendDouble(b, 1, value)
+	return appendVarint(b, 2, uint64(timestamp))
+}
+
+// encodeHistogram encodes a native histogram with integer counts. The message
+// is the same in both protocol versions.
+func encodeHistogram(h *dto.Histogram, timestamp int64) []byte {
+	var b []byte
+	b = appendVarint(b, 1, h.GetSampleCount())
+	b = appendDouble(b, 3, h.GetSampleSum())
+	b = appendVarint(b, 4, protowire.EncodeZigZag(int64(h.GetSchema())))
+	b = appendDouble(b, 5, h.GetZeroThreshold())
+	b = appendVarint(b, 6, h.GetZeroCount())
+	b = appendSpans(b, 8, h.NegativeSpan)
+	b = appendDeltas(b, 9, h.NegativeDelta)
+	b = appendSpans(b, 11, h.PositiveSpan)
+	b = appendDeltas(b, 12, h.PositiveDelta)
+	return appendVarint(b, 15, uint64(timestamp))
+}
+
+func appendSpans(b []byte, num protowire.Number, spans []*dto.BucketSpan) []byte {
+	for _, s := range spans {
+		var span []byte
+		span = appendVarint(span, 1, protowire.EncodeZigZag(int64(s.GetOffset())))
+		span = appendVarint(span, 2, uint64(s.GetLength()))
+		b = appendMessage(b, num, span)
+	}
+	return b
+}
+
+func appendDeltas(b []byte, num protowire.Number, deltas []int64) []byte {
+	if len(deltas) == 0 {
+		return b
+	}
+	var packed []byte
+	for _, d := range deltas {
+		packed = protowire.AppendVarint(packed, protowire.EncodeZigZag(d))
+	}
+	return appendMessage(b, num, packed)
+}
+
+func appendString(b []byte, num protowire.Number, s string) []byte {
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendString(b, s)
+}
+
+func appendMessage(b []byte, num protowire.Number, msg []byte) []byte {
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, msg)
+}
+
+func appendVarint(b []byte, num protowire.Number, v uint64) []byte {
+	b = protowire.AppendTag(b, num, protowire.VarintType)
+	return protowire.AppendVarint(b, v)
+}
+
+func appendDouble(b []byte, num protowire.Number, v float64) []byte {
+	b = protowire.AppendTag(b, num, protowire.Fixed64Type)
+	return protowire.AppendFixed64(b, math.Float64bits(v))
+}
@@ -0,0 +1,256 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/klauspost/compress/snappy"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/promslog"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// protoFields decodes the fields of a protobuf message. Varint and fixed64
+// values are returned as uint64, length delimited values as []byte.
+func protoFields(t *testing.T, b []byte) map[protowire.Number][]any {
+	t.Helper()
+	fields := map[protowire.Number][]any{}
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			t.Fatalf("invalid tag: %v", protowire.ParseError(n))
+		}
+		b = b[n:]
+		var v any
+		switch typ {
+		case protowire.VarintType:
+			v, n = protowire.ConsumeVarint(b)
+		case protowire.Fixed64Type:
+			v, n = protowire.ConsumeFixed64(b)
+		case protowire.BytesType:
+			v, n = protowire.ConsumeBytes(b)
+		default:
+			t.Fatalf("unexpected wire type %v", typ)
+		}
+		if n < 0 {
+			t.Fatalf("invalid field %d: %v", num, protowire.ParseError(n))
+		}
+		fields[num] = append(fields[num], v)
+		b = b[n:]
+	}
+	return fields
+}
+
+func testFamilies(t *testing.T) []*dto.MetricFamily {
+	registry := prometheus.NewRegistry()
+	up := prometheus.NewGauge(prometheus.GaugeOpts{Name: "mysql_up", Help: "Whether the MySQL server is up."})
+	up.Set(1)
+	queries := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "mysql_global_status_commands_total", Help: "Commands."}, []string{"command"})
+	queries.WithLabelValues("select").Add(42)
+	latency := prometheus.NewHistogram(prometheus.HistogramOpts{Name: "mysql_latency_seconds", Help: "Latency.", Buckets: []float64{0.1, 1}})
+	latency.Observe(0.5)
+	registry.MustRegister(up, queries, latency)
+	mfs, err := registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return mfs
+}
+
+func TestFamilySeries(t *testing.T) {
+	var series []rwSeries
+	for _, mf := range testFamilies(t) {
+		series = append(series, familySeries(mf, []rwLabel{{"instance", "db:3306"}, {"job", "mysql"}}, 1000)...)
+	}
+	got := map[string]float64{}
+	for _, s := range series {
+		var parts []string
+		for _, l := range s.labels {
+			parts = append(parts, l.name+"="+l.value)
+		}
+		got[strings.Join(parts, ",")] = s.value
+		if s.timestamp != 1000 {
+			t.Errorf("unexpected timestamp %d", s.timestamp)
+		}
+	}
+	want := map[string]float64{
+		"__name__=mysql_global_status_commands_total,command=select,instance=db:3306,job=mysql": 42,
+		"__name__=mysql_latency_seconds_bucket,instance=db:3306,job=mysql,le=0.1":               0,
+		"__name__=mysql_latency_seconds_bucket,instance=db:3306,job=mysql,le=1":                 1,
+		"__name__=mysql_latency_seconds_bucket,instance=db:3306,job=mysql,le=+Inf":              1,
+		"__name__=mysql_latency_seconds_sum,instance=db:3306,job=mysql":                         0.5,
+		"__name__=mysql_latency_seconds_count,instance=db:3306,job=mysql":                       1,
+		"__name__=mysql_up,instance=db:3306,job=mysql":                                          1,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got series %v, want %v", got, want)
+	}
+}
+
+func TestFamilySeriesExportedLabels(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	hosts := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "mysql_slave_hosts_info", Help: "Replicas."}, []string{"instance", "exported_instance"})
+	hosts.WithLabelValues("replica:3306", "other").Set(1)
+	registry.MustRegister(hosts)
+	mfs, err := registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	series := familySeries(mfs[0], []rwLabel{{"instance", "db:3306"}}, 1000)
+	want := []rwLabel{
+		{"__name__", "mysql_slave_hosts_info"},
+		{"exported_exported_instance", "replica:3306"},
+		{"exported_instance", "other"},
+		{"instance", "db:3306"},
+	}
+	if len(series) != 1 || !reflect.DeepEqual(series[0].labels, want) {
+		t.Errorf("got series %+v, want labels %v", series, want)
+	}
+}
+
+func TestEncodeWriteRequestV2(t *testing.T) {
+	series := familySeries(testFamilies(t)[2], []rwLabel{{"job", "mysql"}}, 1000)
+	req := protoFields(t, encodeWriteRequestV2(series))
+
+	var symbols []string
+	for _, s := range req[4] {
+		symbols = append(symbols, string(s.([]byte)))
+	}
+	if symbols[0] != "" {
+		t.Fatalf("first symbol must be empty, got %q", symbols[0])
+	}
+	if len(req[5]) != 1 {
+		t.Fatalf("expected 1 series, got %d", len(req[5]))
+	}
+	ts := protoFields(t, req[5][0].([]byte))
+	var labels []string
+	for refs := ts[1][0].([]byte); len(refs) > 0; {
+		ref, n := protowire.ConsumeVarint(refs)
+		labels = append(labels, symbols[ref])
+		refs = refs[n:]
+	}
+	if want := []string{"__name__", "mysql_up", "job", "mysql"}; !reflect.DeepEqual(labels, want) {
+		t.Errorf("got labels %v, want %v", labels, want)
+	}
+	sample := protoFields(t, ts[2][0].([]byte))
+	if math.Float64frombits(sample[1][0].(uint64)) != 1 || sample[2][0].(uint64) != 1000 {
+		t.Errorf("unexpected sample %v", sample)
+	}
+	metadata := protoFields(t, ts[5][0].([]byte))
+	if metadata[1][0].(uint64) != 2 || symbols[metadata[3][0].(uint64)] != "Whether the MySQL server is up." {
+		t.Errorf("unexpected metadata %v", metadata)
+	}
+}
+
+func TestRemoteWriteWAL(t *testing.T) {
+	var (
+		status   = http.StatusServiceUnavailable
+		requests [][]byte
+	)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "exporter" || pass != "secret" {
+			t.Errorf("unexpected basic auth %q %q", user, pass)
+		}
+		if r.Header.Get("Content-Encoding") != "snappy" || r.Header.Get("X-Prometheus-Remote-Write-Version") != "0.1.0" {
+			t.Errorf("unexpected headers %v", r.Header)
+		}
+		compressed, _ := io.ReadAll(r.Body)
+		body, err := snappy.Decode(nil, compressed)
+		if err != nil {
+			t.Error(err)
+		}
+		requests = append(requests, body)
+		w.WriteHeader(status)
+	}))
+	defer receiver.Close()
+
+	dir := t.TempDir()
+	passwordFile := filepath.Join(dir, "password")
+	if err := os.WriteFile(passwordFile, []byte("secret\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	u, _ := url.Parse(receiver.URL)
+	client, err := newRemoteWriteClient(u, "exporter", passwordFile, "", time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wal, err := newRemoteWriteWAL(filepath.Join(dir, "wal"), time.Hour, promslog.NewNopLogger())
+	if err != nil {
+		t.Fatal(err)
+	}
+	send := func(protocol string, body []byte) error {
+		return client.send(context.Background(), protocol, body)
+	}
+	pending := func() int {
+		entries, _ := os.ReadDir(wal.dir)
+		return len(entries)
+	}
+
+	now := time.Now()
+	req := encodeWriteRequestV1(familySeries(testFamilies(t)[2], nil, 1000))
+	if err := wal.write(now, "v1", snappy.Encode(nil, req)); err != nil {
+		t.Fatal(err)
+	}
+	if err := wal.replay(now, send); err == nil {
+		t.Fatal("expected error while the endpoint is unavailable")
+	}
+	if pending() != 1 {
+		t.Fatalf("expected the request to be kept, got %d files", pending())
+	}
+
+	status = http.StatusNoContent
+	if err := wal.write(now.Add(time.Second), "v1", snappy.Encode(nil, req)); err != nil {
+		t.Fatal(err)
+	}
+	if err := wal.replay(now, send); err != nil {
+		t.Fatal(err)
+	}
+	if pending() != 0 || len(requests) != 3 {
+		t.Fatalf("expected all requests to be sent, got %d files and %d requests", pending(), len(requests))
+	}
+	ts := protoFields(t, protoFields(t, requests[2])[1][0].([]byte))
+	if len(ts[1]) != 1 || len(ts[2]) != 1 {
+		t.Errorf("unexpected time series %v", ts)
+	}
+
+	status = http.StatusBadRequest
+	if err := wal.write(now, "v1", snappy.Encode(nil, req)); err != nil {
+		t.Fatal(err)
+	}
+	if err := wal.replay(now, send); err != nil || pending() != 0 {
+		t.Errorf("expected rejected request to be dropped, got err %v and %d files", err, pending())
+	}
+
+	status = http.StatusServiceUnavailable
+	if err := wal.write(now, "v1", snappy.Encode(nil, req)); err != nil {
+		t.Fatal(err)
+	}
+	if err := wal.replay(now.Add(2*time.Hour), send); err != nil || pending() != 0 {
+		t.Errorf("expected expired request to be dropped, got err %v and %d files", err, pending())
+	}
+}
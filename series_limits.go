@@ -0,0 +1,71 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/alecthomas/kingpin/v2"
+
+	"github.com/prometheus/mysqld_exporter/collector"
+)
+
+var (
+	seriesLimit = kingpin.Flag(
+		"exporter.series_limit",
+		"Maximum number of series sent by each collector per scrape. 0 means unlimited.",
+	).Default("0").Int()
+	seriesLimitCollectors = kingpin.Flag(
+		"exporter.series_limit.collector",
+		"Series limit of a single collector, e.g. perf_schema.eventsstatements=5000. Overrides --exporter.series_limit. Repeatable.",
+	).Strings()
+	seriesLimitFold = kingpin.Flag(
+		"exporter.series_limit.fold",
+		"Fold series over the limit into one series per metric with all label values set to \"other\" instead of dropping them.",
+	).Default("false").Bool()
+
+	seriesLimits collector.SeriesLimits
+)
+
+// parseSeriesLimits builds the series limits from the default limit and the
+// per-collector overrides in the form name=limit.
+func parseSeriesLimits(defaultLimit int, overrides []string, fold bool, scrapers []collector.Scraper) (collector.SeriesLimits, error) {
+	limits := collector.SeriesLimits{
+		Default:    defaultLimit,
+		Collectors: map[string]int{},
+		Fold:       fold,
+	}
+	known := map[string]bool{}
+	for _, scraper := range scrapers {
+		known[scraper.Name()] = true
+	}
+	for _, override := range overrides {
+		name, value, ok := strings.Cut(override, "=")
+		if !ok {
+			return limits, fmt.Errorf("invalid series limit %q, expected collector=limit", override)
+		}
+		name = strings.TrimPrefix(name, "collect.")
+		if !known[name] {
+			return limits, fmt.Errorf("invalid series limit %q: unknown collector %q", override, name)
+		}
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 0 {
+			return limits, fmt.Errorf("invalid series limit %q: limit must be a non-negative integer", override)
+		}
+		limits.Collectors[name] = limit
+	}
+	return limits, nil
+}
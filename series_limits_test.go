@@ -0,0 +1,44 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/prometheus/mysqld_exporter/collector"
+)
+
+func TestParseSeriesLimits(t *testing.T) {
+	scrapers := []collector.Scraper{collector.ScrapePerfEventsStatements{}, collector.ScrapeProcesslist{}}
+
+	limits, err := parseSeriesLimits(100, []string{"perf_schema.eventsstatements=10", "collect.info_schema.processlist=0"}, true, scrapers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := collector.SeriesLimits{
+		Default:    100,
+		Collectors: map[string]int{"perf_schema.eventsstatements": 10, "info_schema.processlist": 0},
+		Fold:       true,
+	}
+	if !reflect.DeepEqual(limits, expected) {
+		t.Errorf("got %+v, want %+v", limits, expected)
+	}
+
+	for _, override := range []string{"perf_schema.eventsstatements", "unknown=1", "perf_schema.eventsstatements=-1", "perf_schema.eventsstatements=x"} {
+		if _, err := parseSeriesLimits(0, []string{override}, false, scrapers); err == nil {
+			t.Errorf("expected error parsing %q", override)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"os"
+	"strconv"
+
+	"github.com/alecthomas/kingpin/v2"
+
+	"github.com/prometheus/mysqld_exporter/collector"
+	"github.com/prometheus/mysqld_exporter/config"
+)
+
+var (
+	snapshotCmd = kingpin.Command(
+		"snapshot",
+		"Run the queries of every collector against a target and save the results to a file for --replay.",
+	)
+	snapshotSection = snapshotCmd.Flag(
+		"snapshot.section",
+		"Config section of the target.",
+	).Default("client").String()
+	snapshotOutput = snapshotCmd.Flag(
+		"snapshot.output",
+		"File to save the snapshot to.",
+	).Default("mysqld_exporter_snapshot.json").String()
+	snapshotTimeout = snapshotCmd.Flag(
+		"snapshot.timeout",
+		"Timeout for running all collectors.",
+	).Default("60s").Duration()
+	replayFile = kingpin.Flag(
+		"replay",
+		"Serve metrics from a file saved by the snapshot command instead of a live server.",
+	).String()
+
+	// replaySnapshot is loaded from --replay.
+	replaySnapshot *collector.Snapshot
+)
+
+// runSnapshot runs every scraper against the target of the configured
+// section, recording the query results, and saves them.
+func runSnapshot(ctx context.Context, w io.Writer, scrapers []collector.Scraper, logger *slog.Logger) error {
+	if err := reloadConfig(logger); err != nil {
+		return fmt.Errorf("error parsing config %s: %w", *configMycnf, err)
+	}
+	section, ok := c.GetConfig().Sections[*snapshotSection]
+	if !ok {
+		return fmt.Errorf("no [%s] section in %s", *snapshotSection, *configMycnf)
+	}
+	dsn, err := section.FormDSN("")
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, *snapshotTimeout)
+	defer cancel()
+	snapshot := &collector.Snapshot{}
+	_, result, err := gatherTarget(ctx, *snapshotSection, dsn, scrapers, logger, collector.RecordSnapshot(snapshot))
+	if err != nil {
+		return err
+	}
+	if result.Err != nil {
+		return fmt.Errorf("error scraping %s: %w", result.Target, result.Err)
+	}
+	snapshot.Target = result.Target
+	snapshot.Version = result.Version
+	snapshot.Time = result.Time
+
+	err = writeFileAtomic(*snapshotOutput, 0o600, func(w io.Writer) error {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(snapshot)
+	})
+	if err != nil {
+		return err
+	}
+	failed := 0
+	for _, q := range snapshot.Queries {
+		if q.Error != "" {
+			failed++
+		}
+	}
+	fmt.Fprintf(w, "Saved the results of %d queries (%d failed) against %s to %s\n", len(snapshot.Queries), failed, snapshot.Target, *snapshotOutput)
+	return nil
+}
+
+// loadSnapshot reads a snapshot saved by the snapshot command.
+func loadSnapshot(path string) (*collector.Snapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	snapshot := &collector.Snapshot{}
+	if err := json.Unmarshal(data, snapshot); err != nil {
+		return nil, fmt.Errorf("error parsing snapshot: %w", err)
+	}
+	return snapshot, nil
+}
+
+// replayConfig returns a config whose [client] section points to the target
+// of the snapshot, so no config file is needed to replay it.
+func replayConfig(snapshot *collector.Snapshot) *config.Config {
+	section := config.MySqlConfig{User: "replay", Socket: snapshot.Target}
+	if host, port, err := net.SplitHostPort(snapshot.Target); err == nil {
+		section.Socket = ""
+		section.Host = host
+		section.Port, _ = strconv.Atoi(port)
+	}
+	return &config.Config{
+		Sections:      map[string]config.MySqlConfig{"client": section},
+		SectionErrors: map[string]error{},
+	}
+}
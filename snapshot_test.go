@@ -0,0 +1,86 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/prometheus/common/promslog"
+
+	"github.com/prometheus/mysqld_exporter/collector"
+)
+
+const testSnapshot = `{
+  "target": "db.example.com:3306",
+  "version": "8.0.36",
+  "time": "2025-06-01T12:00:00Z",
+  "queries": [
+    {
+      "query": "SELECT @@version;",
+      "columns": [{"name": "@@version", "type": "VARCHAR"}],
+      "rows": [["8.0.36-28"]]
+    },
+    {
+      "query": "SHOW GLOBAL STATUS",
+      "columns": [{"name": "Variable_name", "type": "VARCHAR"}, {"name": "Value", "type": "VARCHAR"}],
+      "rows": [["Uptime", "42"], ["Ssl_version", null]]
+    }
+  ]
+}`
+
+func TestReplaySnapshot(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+	if err := os.WriteFile(path, []byte(testSnapshot), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	snapshot, err := loadSnapshot(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	replaySnapshot = snapshot
+	defer func() { replaySnapshot = nil }()
+
+	if err := reloadConfig(promslog.NewNopLogger()); err != nil {
+		t.Fatal(err)
+	}
+	dsn, err := c.GetConfig().Sections["client"].FormDSN("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	mfs, result, err := gatherTarget(context.Background(), "client", dsn, []collector.Scraper{collector.ScrapeGlobalStatus{}}, promslog.NewNopLogger())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Err != nil || result.Target != "db.example.com:3306" || result.Version != "8.0.36" {
+		t.Errorf("unexpected result %+v", result)
+	}
+	values := map[string]float64{}
+	for _, mf := range mfs {
+		if m := mf.Metric[0]; m.Gauge != nil {
+			values[mf.GetName()] = m.Gauge.GetValue()
+		} else if m.Untyped != nil {
+			values[mf.GetName()] = m.Untyped.GetValue()
+		}
+	}
+	if values["mysql_up"] != 1 || values["mysql_global_status_uptime"] != 42 {
+		t.Errorf("unexpected metrics %v", values)
+	}
+
+	if _, err := loadSnapshot(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("expected error loading a missing snapshot")
+	}
+}
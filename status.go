@@ -0,0 +1,193 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"cmp"
+	"encoding/json"
+	"html/template"
+	"log/slog"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/prometheus/mysqld_exporter/collector"
+)
+
+// statusRetention is how long a target stays on the status page after its
+// last scrape.
+const statusRetention = time.Hour
+
+var scrapeStatus = newStatusStore()
+
+// targetStatus is the last scrape result of a target, as shown on the status page.
+type targetStatus struct {
+	Target          string            `json:"target"`
+	AuthModule      string            `json:"auth_module"`
+	Profile         string            `json:"profile,omitempty"`
+	Path            string            `json:"path,omitempty"`
+	Version         string            `json:"version,omitempty"`
+	Flavor          string            `json:"flavor,omitempty"`
+	LastScrape      time.Time         `json:"last_scrape"`
+	DurationSeconds float64           `json:"duration_seconds"`
+	Error           string            `json:"error,omitempty"`
+	Incomplete      bool              `json:"incomplete,omitempty"`
+	Collectors      []collectorStatus `json:"collectors"`
+}
+
+type collectorStatus struct {
+	Name            string  `json:"name"`
+	DurationSeconds float64 `json:"duration_seconds"`
+	Series          int     `json:"series"`
+	Error           string  `json:"error,omitempty"`
+	TimedOut        bool    `json:"timed_out,omitempty"`
+	Suspended       bool    `json:"suspended,omitempty"`
+	Shed            bool    `json:"shed,omitempty"`
+}
+
+// statusStore keeps the last scrape result of every recently scraped target.
+type statusStore struct {
+	mu      sync.Mutex
+	targets map[string]targetStatus
+}
+
+func newStatusStore() *statusStore {
+	return &statusStore{targets: make(map[string]targetStatus)}
+}
+
+func errString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
+// record stores the result of a scrape done with the given auth module and
+// profile, if any.
+func (s *statusStore) record(authModule string, profile *scrapeProfile, result collector.ScrapeResult) {
+	status := targetStatus{
+		Target:          result.Target,
+		AuthModule:      authModule,
+		Version:         result.Version,
+		Flavor:          result.Flavor,
+		LastScrape:      result.Time,
+		DurationSeconds: result.Duration.Seconds(),
+		Error:           errString(result.Err),
+		Incomplete:      result.Incomplete,
+		Collectors:      make([]collectorStatus, 0, len(result.Collectors)),
+	}
+	if profile != nil {
+		status.Profile = profile.Name
+		status.Path = profilePath(profile)
+	}
+	for _, c := range result.Collectors {
+		status.Collectors = append(status.Collectors, collectorStatus{
+			Name:            c.Name,
+			DurationSeconds: c.Duration.Seconds(),
+			Series:          c.Series,
+			Error:           errString(c.Err),
+			TimedOut:        c.TimedOut,
+			Suspended:       c.Suspended,
+			Shed:            c.Shed,
+		})
+	}
+	slices.SortFunc(status.Collectors, func(a, b collectorStatus) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := authModule + "\x00" + status.Profile + "\x00" + result.Target
+	// A scrape failing before running any collector, e.g. because the
+	// connection failed, keeps the collectors of the previous scrape.
+	if result.Err != nil && len(result.Collectors) == 0 {
+		status.Collectors = s.targets[key].Collectors
+	}
+	s.targets[key] = status
+	for key, t := range s.targets {
+		if time.Since(t.LastScrape) > statusRetention {
+			delete(s.targets, key)
+		}
+	}
+}
+
+// list returns the stored targets ordered by target, auth module and profile.
+func (s *statusStore) list() []targetStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	targets := make([]targetStatus, 0, len(s.targets))
+	for _, t := range s.targets {
+		targets = append(targets, t)
+	}
+	slices.SortFunc(targets, func(a, b targetStatus) int {
+		return cmp.Or(cmp.Compare(a.Target, b.Target), cmp.Compare(a.AuthModule, b.AuthModule), cmp.Compare(a.Profile, b.Profile))
+	})
+	return targets
+}
+
+var statusTemplate = template.Must(template.New("status").Parse(`<html lang="en">
+  <head>
+    <meta charset="UTF-8">
+    <title>MySQLd Exporter Status</title>
+    <style>
+      body { font-family: sans-serif; }
+      table { border-collapse: collapse; margin-bottom: 1rem; }
+      th, td { border: 1px solid #ccc; padding: 0.25rem 0.5rem; text-align: left; }
+      .error { color: #c00; }
+    </style>
+  </head>
+  <body>
+    <h1>MySQLd Exporter Status</h1>
+    <p><a href="?format=json">JSON</a></p>
+    {{- range . }}
+    <h2>{{ .Target }} ({{ .AuthModule }}{{ if .Profile }}, profile {{ .Profile }} at {{ .Path }}{{ end }})</h2>
+    <p>
+      Version: {{ or .Version "unknown" }}, flavor: {{ or .Flavor "unknown" }}<br>
+      Last scrape: {{ .LastScrape.Format "2006-01-02T15:04:05Z07:00" }} ({{ printf "%.3f" .DurationSeconds }}s)
+      {{- if .Error }}<br><span class="error">Error: {{ .Error }}</span>{{ end }}
+      {{- if .Incomplete }}<br><span class="error">Incomplete: ran out of time</span>{{ end }}
+    </p>
+    {{- if .Collectors }}
+    <table>
+      <tr><th>Collector</th><th>Duration (s)</th><th>Series</th><th>Last error</th></tr>
+      {{- range .Collectors }}
+      <tr><td>{{ .Name }}</td><td>{{ printf "%.3f" .DurationSeconds }}</td><td>{{ .Series }}</td><td class="error">{{ if .TimedOut }}timed out{{ else }}{{ if .Suspended }}suspended: {{ end }}{{ .Error }}{{ end }}</td></tr>
+      {{- end }}
+    </table>
+    {{- end }}
+    {{- else }}
+    <p>No targets scraped yet.</p>
+    {{- end }}
+  </body>
+</html>
+`))
+
+// handleStatus serves the status page as HTML, or as JSON with ?format=json.
+func handleStatus(logger *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		targets := scrapeStatus.list()
+		if r.URL.Query().Get("format") == "json" {
+			w.Header().Set("Content-Type", "application/json")
+			if err := json.NewEncoder(w).Encode(targets); err != nil {
+				logger.Error("Error encoding status", "err", err)
+			}
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if err := statusTemplate.Execute(w, targets); err != nil {
+			logger.Error("Error rendering status page", "err", err)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/promslog"
+
+	"github.com/prometheus/mysqld_exporter/collector"
+)
+
+func TestStatusStore(t *testing.T) {
+	s := newStatusStore()
+	s.record("client", nil, collector.ScrapeResult{
+		Target:  "db2:3306",
+		Version: "8.0.36",
+		Flavor:  "mysql",
+		Time:    time.Now(),
+		Collectors: []collector.CollectorResult{
+			{Name: "slave_status", Duration: time.Second, Series: 3},
+			{Name: "global_status", Duration: time.Second, Series: 500, Err: errors.New("boom")},
+		},
+	})
+	s.record("client", nil, collector.ScrapeResult{
+		Target: "db1:3306",
+		Time:   time.Now(),
+		Err:    errors.New("connection refused"),
+	})
+	// Stale targets are dropped on the next record.
+	s.record("client", nil, collector.ScrapeResult{
+		Target: "db3:3306",
+		Time:   time.Now().Add(-2 * statusRetention),
+	})
+	s.record("client", nil, collector.ScrapeResult{
+		Target: "db1:3306",
+		Time:   time.Now(),
+		Err:    errors.New("access denied"),
+	})
+
+	targets := s.list()
+	if len(targets) != 2 {
+		t.Fatalf("expected 2 targets, got %d", len(targets))
+	}
+	if targets[0].Target != "db1:3306" || targets[0].Error != "access denied" {
+		t.Errorf("unexpected first target: %+v", targets[0])
+	}
+	if got := targets[1].Collectors; len(got) != 2 || got[0].Name != "global_status" || got[0].Error != "boom" || got[0].Series != 500 {
+		t.Errorf("unexpected collectors: %+v", got)
+	}
+
+	// A failed connection keeps the collectors of the previous scrape.
+	s.record("client", nil, collector.ScrapeResult{
+		Target: "db2:3306",
+		Time:   time.Now(),
+		Err:    errors.New("connection refused"),
+	})
+	if got := s.list()[1]; got.Error != "connection refused" || len(got.Collectors) != 2 {
+		t.Errorf("unexpected target after a failed connection: %+v", got)
+	}
+
+	// Profiles scraping the same target are kept apart.
+	s.record("client", &scrapeProfile{Name: "fast"}, collector.ScrapeResult{
+		Target: "db2:3306",
+		Time:   time.Now(),
+	})
+	targets = s.list()
+	if len(targets) != 3 || targets[1].Profile != "" || targets[2].Profile != "fast" || len(targets[1].Collectors) != 2 {
+		t.Errorf("unexpected targets with a profile: %+v", targets)
+	}
+}
+
+func TestHandleStatus(t *testing.T) {
+	scrapeStatus = newStatusStore()
+	scrapeStatus.record("client", nil, collector.ScrapeResult{
+		Target:     "db1:3306",
+		Version:    "10.11.6",
+		Flavor:     "mariadb",
+		Time:       time.Now(),
+		Collectors: []collector.CollectorResult{{Name: "global_status", Series: 42}},
+	})
+	scrapeStatus.record("client", &scrapeProfile{Name: "fast"}, collector.ScrapeResult{
+		Target: "db1:3306",
+		Time:   time.Now(),
+	})
+	handler := handleStatus(promslog.NewNopLogger())
+
+	rec := httptest.NewRecorder()
+	handler(rec, httptest.NewRequest("GET", "/status?format=json", nil))
+	var targets []targetStatus
+	if err := json.Unmarshal(rec.Body.Bytes(), &targets); err != nil {
+		t.Fatal(err)
+	}
+	if len(targets) != 2 || targets[0].Flavor != "mariadb" || targets[0].Collectors[0].Series != 42 || targets[1].Profile != "fast" || targets[1].Path != profilePath(&scrapeProfile{Name: "fast"}) {
+		t.Errorf("unexpected status: %+v", targets)
+	}
+
+	rec = httptest.NewRecorder()
+	handler(rec, httptest.NewRequest("GET", "/status", nil))
+	if body := rec.Body.String(); !strings.Contains(body, "db1:3306") || !strings.Contains(body, "<td>global_status</td>") {
+		t.Errorf("status page is missing target details:\n%s", body)
+	}
+	if body := rec.Body.String(); !strings.Contains(body, "profile fast at ") {
+		t.Errorf("status page is missing the profile:\n%s", body)
+	}
+}
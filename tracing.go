@@ -0,0 +1,96 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/alecthomas/kingpin/v2"
+	"github.com/prometheus/common/version"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+var (
+	tracingEndpoint = kingpin.Flag(
+		"tracing.endpoint",
+		"URL of the OTLP receiver to send traces of scrapes to, e.g. http://collector:4317. Tracing is disabled if empty.",
+	).String()
+	tracingProtocol = kingpin.Flag(
+		"tracing.protocol",
+		"OTLP transport protocol for traces.",
+	).Default("grpc").Enum("grpc", "http/protobuf")
+	tracingSampleRatio = kingpin.Flag(
+		"tracing.sample_ratio",
+		"Fraction of scrapes to trace. Scrapes with a traceparent header follow the sampling decision of the caller.",
+	).Default("1").Float64()
+
+	// tracerProvider is nil unless tracing is enabled.
+	tracerProvider trace.TracerProvider
+)
+
+const tracerName = "github.com/prometheus/mysqld_exporter"
+
+// setupTracing sets up the tracer provider exporting spans to the configured
+// endpoint. The returned function flushes and stops it.
+func setupTracing(ctx context.Context) (func(context.Context) error, error) {
+	var (
+		exporter *otlptrace.Exporter
+		err      error
+	)
+	if *tracingProtocol == "http/protobuf" {
+		exporter, err = otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(*tracingEndpoint))
+	} else {
+		exporter, err = otlptracegrpc.New(ctx, otlptracegrpc.WithEndpointURL(*tracingEndpoint))
+	}
+	if err != nil {
+		return nil, err
+	}
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(*tracingSampleRatio))),
+		sdktrace.WithResource(resource.NewSchemaless(
+			attribute.String("service.name", "mysqld_exporter"),
+			attribute.String("service.version", version.Version),
+		)),
+	)
+	tracerProvider = tp
+	return tp.Shutdown, nil
+}
+
+// startScrapeSpan starts the root span of a scrape request, continuing the
+// trace of the caller if the request has a traceparent header.
+func startScrapeSpan(r *http.Request, target, authModule string) (context.Context, trace.Span) {
+	tp := tracerProvider
+	if tp == nil {
+		tp = noop.NewTracerProvider()
+	}
+	ctx := propagation.TraceContext{}.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+	attrs := []attribute.KeyValue{
+		attribute.String("url.path", r.URL.Path),
+		attribute.String("mysql.auth_module", authModule),
+	}
+	if target != "" {
+		attrs = append(attrs, attribute.String("mysql.target", target))
+	}
+	return tp.Tracer(tracerName).Start(ctx, "scrape", trace.WithSpanKind(trace.SpanKindServer), trace.WithAttributes(attrs...))
+}
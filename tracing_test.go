@@ -0,0 +1,74 @@
+// Copyright 2025 The Prometheus Authors
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/prometheus/common/promslog"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/prometheus/mysqld_exporter/collector"
+)
+
+func TestScrapeTracing(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+	if err := os.WriteFile(path, []byte(testSnapshot), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	snapshot, err := loadSnapshot(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	spans := tracetest.NewInMemoryExporter()
+	replaySnapshot = snapshot
+	tracerProvider = sdktrace.NewTracerProvider(sdktrace.WithSyncer(spans))
+	defer func() { replaySnapshot, tracerProvider = nil, nil }()
+	if err := reloadConfig(promslog.NewNopLogger()); err != nil {
+		t.Fatal(err)
+	}
+
+	const (
+		traceID  = "4bf92f3577b34da6a3ce929d0e0e4736"
+		parentID = "00f067aa0ba902b7"
+	)
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	req.Header.Set("traceparent", "00-"+traceID+"-"+parentID+"-01")
+	rec := httptest.NewRecorder()
+	newHandler([]collector.Scraper{collector.ScrapeGlobalStatus{}}, promslog.NewNopLogger(), nil)(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("unexpected status %d", rec.Code)
+	}
+
+	names := map[string]bool{}
+	for _, s := range spans.GetSpans() {
+		names[s.Name] = true
+		if s.SpanContext.TraceID().String() != traceID {
+			t.Errorf("span %s has trace ID %s, want %s", s.Name, s.SpanContext.TraceID(), traceID)
+		}
+		if s.Name == "scrape" && s.Parent.SpanID().String() != parentID {
+			t.Errorf("root span has parent %s, want %s", s.Parent.SpanID(), parentID)
+		}
+	}
+	for _, name := range []string{"scrape", "connect", "scrape global_status", "query"} {
+		if !names[name] {
+			t.Errorf("missing span %q, got %v", name, names)
+		}
+	}
+}